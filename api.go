@@ -1,56 +1,150 @@
 package whalealertapi
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"time"
 )
 
+// WhaleAlertAPI is client of Whale Alert API
+// Client created by New is never modified by its methods, so it is safe for concurrent use.
+// Use With to derive client with different configuration.
 type WhaleAlertAPI struct {
-	url    string
-	key    string
-	client *http.Client
+	url         string
+	key         string
+	client      *http.Client
+	transport   http.RoundTripper
+	middlewares []Middleware
+	timeout     time.Duration
+	userAgent   string
+	retry       RetryPolicy
+	limiter     *RateLimiter
+	quota       *quotaTracker
+	catalog     *BlockchainCatalog
+	cache       *responseCache
+	cachePolicy CachePolicy
+	flights     *flightGroup
+	observer    Observer
+	logger      *slog.Logger
+	logLevels   LogLevels
+	plan        Plan
+	// skipValidation disables validation of requests before sending
+	skipValidation bool
 }
 
-func New() *WhaleAlertAPI {
-	return &WhaleAlertAPI{
-		client: &http.Client{},
+// New returns client configured with given options, DefaultURL is used unless WithURL is given
+//
+//	api := New(WithAccessKey("key"), WithRateLimit(10, time.Minute), WithRetryPolicy(DefaultRetryPolicy()))
+func New(opts ...Option) *WhaleAlertAPI {
+	api := &WhaleAlertAPI{
+		url:         DefaultURL,
+		client:      &http.Client{Timeout: DefaultTimeout},
+		plan:        DefaultPlan,
+		quota:       &quotaTracker{},
+		cachePolicy: DefaultCachePolicy(),
+		logLevels:   DefaultLogLevels(),
 	}
+	api.apply(opts)
+	return api
+}
+
+// With returns copy of the client with options applied, the client itself is not modified
+// Derived client shares rate limiter and quota with the original one, unless they are replaced by options
+func (api *WhaleAlertAPI) With(opts ...Option) *WhaleAlertAPI {
+	derived := *api
+	derived.apply(opts)
+	return &derived
 }
 
+// The methods below modify the client in place and are kept for compatibility.
+// They must not be called while the client is in use by other goroutines, use With instead.
+
 func (api *WhaleAlertAPI) WithDefaultURL() *WhaleAlertAPI {
-	api.url = "https://api.whale-alert.io/v1"
+	WithURL(DefaultURL)(api)
 	return api
 }
 
 func (api *WhaleAlertAPI) WithCustomURL(url string) *WhaleAlertAPI {
-	api.url = url
+	WithURL(url)(api)
 	return api
 }
 
 func (api *WhaleAlertAPI) WithAccessKey(key string) *WhaleAlertAPI {
-	api.key = key
+	WithAccessKey(key)(api)
 	return api
 }
 
+// RateLimiter returns limiter used by the client, or nil when requests are not limited
+func (api WhaleAlertAPI) RateLimiter() *RateLimiter {
+	return api.limiter
+}
+
+// Status calls /status endpoint, it is StatusContext with background context
 func (api WhaleAlertAPI) Status() (*StatusResponse, error) {
-	res, err := get[StatusResponse](api.client, api.url, api.key, "/status", []APIArgument{})
+	return api.StatusContext(context.Background())
+}
+
+// StatusContext calls /status endpoint, request is bound to given context
+func (api WhaleAlertAPI) StatusContext(ctx context.Context) (*StatusResponse, error) {
+	res, _, err := api.StatusWithMeta(ctx)
+	return res, err
+}
+
+// StatusWithMeta calls /status endpoint and returns response metadata too
+func (api WhaleAlertAPI) StatusWithMeta(ctx context.Context) (*StatusResponse, *ResponseMeta, error) {
+	return getWithMeta[StatusResponse](ctx, api, "/status", []APIArgument{})
+}
+
+// Transaction calls /transaction endpoint, it is TransactionContext with background context
+func (api WhaleAlertAPI) Transaction(blockchain string, hash string) (*TransactionResponse, error) {
+	return api.TransactionContext(context.Background(), blockchain, hash)
+}
+
+// TransactionContext calls /transaction endpoint, request is bound to given context
+func (api WhaleAlertAPI) TransactionContext(ctx context.Context, blockchain string, hash string) (*TransactionResponse, error) {
+	res, _, err := api.TransactionWithMeta(ctx, blockchain, hash)
 	return res, err
 }
 
-func (api WhaleAlertAPI) Transaction(blockchain, hash string) (*TransactionResponse, error) {
+// TransactionWithMeta calls /transaction endpoint and returns response metadata too
+// When client has blockchain catalog, blockchain is checked before the call
+func (api WhaleAlertAPI) TransactionWithMeta(ctx context.Context, blockchain string, hash string) (*TransactionResponse, *ResponseMeta, error) {
 	if blockchain == "" || hash == "" {
-		return nil, fmt.Errorf("blockchain and hash are required")
+		return nil, nil, fmt.Errorf("blockchain and hash are required")
 	}
-	endpoint := fmt.Sprintf("/transaction/%s/%s", blockchain, hash)
-	res, err := get[TransactionResponse](api.client, api.url, api.key, endpoint, []APIArgument{})
-	return res, err
+	if api.catalog != nil {
+		if _, err := api.catalog.Lookup(ctx, BlockchainName(blockchain)); err != nil {
+			return nil, nil, err
+		}
+	}
+	endpoint := endpointPath("transaction", blockchain, hash)
+	return getWithMeta[TransactionResponse](ctx, api, endpoint, []APIArgument{})
 }
 
+// Transactions calls /transactions endpoint, it is TransactionsContext with background context
 func (api WhaleAlertAPI) Transactions(start uint, args TransactionsRequest) (*TransactionsResponse, error) {
+	return api.TransactionsContext(context.Background(), start, args)
+}
+
+// TransactionsContext calls /transactions endpoint, request is bound to given context
+// Unless disabled with WithValidation, request is validated against client plan before it is sent
+func (api WhaleAlertAPI) TransactionsContext(ctx context.Context, start uint, args TransactionsRequest) (*TransactionsResponse, error) {
+	res, _, err := api.TransactionsWithMeta(ctx, start, args)
+	return res, err
+}
+
+// TransactionsWithMeta calls /transactions endpoint and returns response metadata too
+func (api WhaleAlertAPI) TransactionsWithMeta(ctx context.Context, start uint, args TransactionsRequest) (*TransactionsResponse, *ResponseMeta, error) {
 	if start <= 0 {
-		return nil, fmt.Errorf("start must be greater than 0")
+		return nil, nil, fmt.Errorf("start must be greater than 0")
 	}
 	args.Start = start
-	res, err := get[TransactionsResponse](api.client, api.url, api.key, "/transactions", args.toAPIArguments())
-	return res, err
+	if !api.skipValidation {
+		if err := args.ValidateFor(api.plan); err != nil {
+			return nil, nil, err
+		}
+	}
+	return getWithMeta[TransactionsResponse](ctx, api, "/transactions", args.toAPIArguments())
 }
@@ -1,10 +1,13 @@
 package whalealertapi_test
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	whalealertapi "github.com/devbay-io/whale_alert_api_client"
 )
@@ -59,6 +62,13 @@ func TestStatus(t *testing.T) {
 	if err.Error() != "invalid api_key" {
 		t.Errorf("Expected %s got: %s", "invalid api_key", err.Error())
 	}
+	if !errors.Is(err, whalealertapi.ErrUnauthorized) {
+		t.Errorf("Expected %s got: %s", whalealertapi.ErrUnauthorized, err)
+	}
+	var apiErr *whalealertapi.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized || apiErr.Endpoint != "/status" {
+		t.Errorf("Expected APIError for /status with status %d, got: %v", http.StatusUnauthorized, apiErr)
+	}
 }
 
 const (
@@ -141,6 +151,9 @@ func TestTransaction(t *testing.T) {
 	if err.Error() != "invalid value for blockchain parameter" {
 		t.Errorf("Expected %s got: %s", "invalid value for hash parameter", err)
 	}
+	if !errors.Is(err, whalealertapi.ErrInvalidParameter) {
+		t.Errorf("Expected %s got: %s", whalealertapi.ErrInvalidParameter, err)
+	}
 
 }
 
@@ -194,3 +207,89 @@ func TestTransactions(t *testing.T) {
 		t.Errorf("Expected %d got: %d", 2, res.Count)
 	}
 }
+
+func TestContextCancellation(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	api := whalealertapi.New().WithCustomURL(server.URL).WithAccessKey("CORRECT")
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	res, err := api.StatusContext(ctx)
+	if res != nil {
+		t.Errorf("Expected nil, got %v", res)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected %s got: %v", context.DeadlineExceeded, err)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	_, err = api.TransactionContext(ctx, ethChain, hashCorrect)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected %s got: %v", context.Canceled, err)
+	}
+	_, err = api.TransactionsContext(ctx, 1679774558, whalealertapi.TransactionsRequest{})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected %s got: %v", context.Canceled, err)
+	}
+}
+
+func TestWithMeta(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "abc")
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if strings.HasPrefix(r.URL.Path, "/transaction/") {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"result":"error","message":"invalid value for hash parameter"}`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"result":"success","cursor":"0-0-0","count":0}`))
+	}))
+	defer server.Close()
+
+	policy := whalealertapi.DefaultRetryPolicy()
+	policy.BaseBackoff = time.Millisecond
+	api := whalealertapi.New(whalealertapi.WithURL(server.URL), whalealertapi.WithAccessKey("SECRET"), whalealertapi.WithRetryPolicy(policy))
+	res, meta, err := api.TransactionsWithMeta(context.Background(), 1679774558, whalealertapi.TransactionsRequest{})
+	if err != nil {
+		t.Fatalf("Expected OK got error: %s", err)
+	}
+	if res.Cursor != "0-0-0" {
+		t.Errorf("Expected %s got: %s", "0-0-0", res.Cursor)
+	}
+	if meta.StatusCode != http.StatusOK || meta.Attempts != 2 || meta.Header.Get("X-Request-Id") != "abc" {
+		t.Errorf("Unexpected meta %+v", meta)
+	}
+	if meta.URL != server.URL+"/transactions?start=1679774558" || meta.Duration <= 0 {
+		t.Errorf("Unexpected meta %+v", meta)
+	}
+
+	_, meta, err = api.TransactionWithMeta(context.Background(), ethChain, hashIncorrect)
+	if err == nil {
+		t.Errorf("Expected error, got nil")
+	}
+	if meta.StatusCode != http.StatusBadRequest || meta.Attempts != 1 {
+		t.Errorf("Unexpected meta %+v", meta)
+	}
+
+	_, meta, err = api.With(whalealertapi.WithAccessKey("")).StatusWithMeta(context.Background())
+	if !errors.Is(err, whalealertapi.ErrMissingAccessKey) || meta != nil {
+		t.Errorf("Expected %s and no meta, got %v and %+v", whalealertapi.ErrMissingAccessKey, err, meta)
+	}
+}
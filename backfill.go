@@ -0,0 +1,150 @@
+package whalealertapi
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// MaxTransactionsWindow is the longest time range accepted by /transactions in single request
+const MaxTransactionsWindow = time.Hour
+
+// BackfillProgress describes state of Backfiller after finishing a window
+type BackfillProgress struct {
+	// Window is number of finished windows, starting from 1
+	Window int
+	// Windows is total number of windows
+	Windows      int
+	WindowStart  uint
+	WindowEnd    uint
+	Transactions int
+}
+
+// Backfiller fetches all transactions from time range which can be longer than API allows
+// Range is split into windows, every window is paginated with cursor
+type Backfiller struct {
+	api      WhaleAlertAPI
+	from     uint
+	to       uint
+	filter   TransactionsRequest
+	window   time.Duration
+	progress func(BackfillProgress)
+	store    CheckpointStore
+}
+
+// NewBackfiller returns Backfiller for transactions matching filter in range (from, to]
+// Like in /transactions, from is exclusive and to is inclusive
+func (api WhaleAlertAPI) NewBackfiller(from, to uint, filter TransactionsRequest) *Backfiller {
+	return &Backfiller{
+		api:    api,
+		from:   from,
+		to:     to,
+		filter: filter,
+		window: MaxTransactionsWindow,
+	}
+}
+
+// Backfill returns all transactions matching filter in range (from, to], see Backfiller.Run
+func (api WhaleAlertAPI) Backfill(ctx context.Context, from, to uint, filter TransactionsRequest) ([]Transaction, error) {
+	return api.NewBackfiller(from, to, filter).Run(ctx)
+}
+
+// WithWindow sets length of single window, it should not be longer than API allows
+func (b *Backfiller) WithWindow(window time.Duration) *Backfiller {
+	b.window = window
+	return b
+}
+
+// WithProgress sets function called after every finished window
+func (b *Backfiller) WithProgress(progress func(BackfillProgress)) *Backfiller {
+	b.progress = progress
+	return b
+}
+
+// WithCheckpointStore sets store used to resume backfill, checkpoint is saved after every finished window
+// When Run starts from a checkpoint inside the range, only transactions after it are fetched and returned
+func (b *Backfiller) WithCheckpointStore(store CheckpointStore) *Backfiller {
+	b.store = store
+	return b
+}
+
+// Run fetches all windows one by one and returns transactions deduplicated by ID and sorted by timestamp
+// Requests wait for the client rate limiter. On error, transactions fetched so far are returned with the error.
+func (b *Backfiller) Run(ctx context.Context) ([]Transaction, error) {
+	if b.from == 0 || b.to <= b.from {
+		return nil, fmt.Errorf("invalid backfill range: from must be greater than 0 and lower than to")
+	}
+	step := uint(b.window / time.Second)
+	if step == 0 {
+		return nil, fmt.Errorf("backfill window must be at least 1s")
+	}
+	from := b.from
+	if b.store != nil {
+		checkpoint, err := b.store.Load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if checkpoint != nil && checkpoint.Timestamp > from && checkpoint.Timestamp <= b.to {
+			from = checkpoint.Timestamp
+		}
+	}
+	windows := int((b.to - from + step - 1) / step)
+	seen := map[string]struct{}{}
+	result := []Transaction{}
+	window := 0
+	for start := from; start < b.to; start += step {
+		end := start + step
+		if end > b.to {
+			end = b.to
+		}
+		window++
+		filter := b.filter
+		filter.End = end
+		filter.Cursor = ""
+		it := b.api.IterateTransactions(ctx, start, filter)
+		for it.Next() {
+			tx := it.Transaction()
+			if _, ok := seen[tx.ID]; ok {
+				continue
+			}
+			seen[tx.ID] = struct{}{}
+			result = append(result, tx)
+		}
+		if err := it.Err(); err != nil {
+			sortTransactions(result)
+			return result, err
+		}
+		if b.store != nil {
+			if err := b.store.Save(ctx, Checkpoint{Timestamp: end, UpdatedAt: time.Now()}); err != nil {
+				sortTransactions(result)
+				return result, err
+			}
+		}
+		if b.progress != nil {
+			b.progress(BackfillProgress{
+				Window:       window,
+				Windows:      windows,
+				WindowStart:  start,
+				WindowEnd:    end,
+				Transactions: len(result),
+			})
+		}
+	}
+	sortTransactions(result)
+	return result, nil
+}
+
+// sortTransactions sorts transactions by timestamp and then by numeric ID
+func sortTransactions(transactions []Transaction) {
+	sort.SliceStable(transactions, func(i, j int) bool {
+		a, b := transactions[i], transactions[j]
+		if a.Timestamp != b.Timestamp {
+			return a.Timestamp < b.Timestamp
+		}
+		if len(a.ID) != len(b.ID) {
+			return len(a.ID) < len(b.ID)
+		}
+		return a.ID < b.ID
+	})
+}
@@ -0,0 +1,84 @@
+package whalealertapi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	whalealertapi "github.com/devbay-io/whale_alert_api_client"
+	"github.com/devbay-io/whale_alert_api_client/whalealerttest"
+)
+
+func TestBackfill(t *testing.T) {
+	const from = 1679770000
+	transactions := []whalealertapi.Transaction{}
+	// One transaction every 10 minutes, for 5 hours
+	for i := 0; i < 30; i++ {
+		transactions = append(transactions, whalealertapi.Transaction{
+			Blockchain: "ethereum",
+			Symbol:     "usdt",
+			ID:         strconv.Itoa(100 - i),
+			Hash:       strconv.Itoa(i),
+			Timestamp:  uint(from + 600*i),
+			AmountUSD:  whalealertapi.NewDecimalFromInt(1000000),
+		})
+	}
+	srv := whalealerttest.NewServer().AddTransactions(transactions...)
+	defer srv.Close()
+
+	progress := []whalealertapi.BackfillProgress{}
+	res, err := srv.Client().
+		NewBackfiller(from, from+5*3600, whalealertapi.TransactionsRequest{Limit: 4}).
+		WithProgress(func(p whalealertapi.BackfillProgress) { progress = append(progress, p) }).
+		Run(context.Background())
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err)
+	}
+	// Start is exclusive, so the first transaction is not returned
+	if len(res) != 29 {
+		t.Fatalf("Expected %d transactions, got %d", 29, len(res))
+	}
+	for i, tx := range res {
+		if tx.Timestamp != uint(from+600*(i+1)) {
+			t.Errorf("Expected transactions sorted by timestamp, got %d at %d", tx.Timestamp, i)
+		}
+	}
+	if len(progress) != 5 {
+		t.Fatalf("Expected %d progress reports, got %d", 5, len(progress))
+	}
+	last := progress[4]
+	if last.Window != 5 || last.Windows != 5 || last.WindowEnd != from+5*3600 || last.Transactions != 29 {
+		t.Errorf("Unexpected last progress %+v", last)
+	}
+}
+
+func TestBackfillError(t *testing.T) {
+	srv := whalealerttest.NewServer().AddTransactions(
+		whalealertapi.Transaction{Blockchain: "ethereum", ID: "1", Timestamp: 1679770100},
+		whalealertapi.Transaction{Blockchain: "ethereum", ID: "2", Timestamp: 1679774000},
+	)
+	defer srv.Close()
+
+	api := srv.Client()
+	_, err := api.Backfill(context.Background(), 1679770000, 1679770000, whalealertapi.TransactionsRequest{})
+	if err == nil {
+		t.Errorf("Expected error for empty range")
+	}
+
+	// Second window fails, transactions from the first one are returned
+	res, err := api.NewBackfiller(1679770000, 1679777000, whalealertapi.TransactionsRequest{}).
+		WithWindow(30 * time.Minute).
+		WithProgress(func(whalealertapi.BackfillProgress) {
+			srv.FailNext("/transactions", http.StatusServiceUnavailable, "unavailable")
+		}).
+		Run(context.Background())
+	if !errors.Is(err, whalealertapi.ErrServerError) {
+		t.Errorf("Expected %s, got %v", whalealertapi.ErrServerError, err)
+	}
+	if len(res) != 1 || res[0].ID != "1" {
+		t.Errorf("Expected transaction 1, got %v", res)
+	}
+}
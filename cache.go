@@ -0,0 +1,259 @@
+package whalealertapi
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultCacheSize is number of responses kept by memory cache when size is not positive
+const DefaultCacheSize = 1000
+
+// Cache keeps raw bodies of successful responses, keys are request urls without access key
+// Implementations must be safe for concurrent use
+type Cache interface {
+	// Get returns value stored with key, false when it is missing or expired
+	Get(key string) ([]byte, bool)
+	// Set stores value for ttl
+	Set(key string, value []byte, ttl time.Duration)
+}
+
+// CachePolicy is how long responses of each endpoint are cached, zero disables caching of the endpoint
+type CachePolicy struct {
+	// Transaction is used for /transaction responses with at least one transaction, which never change
+	Transaction time.Duration
+	// Status is used for /status responses
+	Status time.Duration
+	// Transactions is used for /transactions responses, only when end of the window is in the past
+	Transactions time.Duration
+}
+
+// DefaultCachePolicy returns policy caching transactions for a day and status for a minute
+func DefaultCachePolicy() CachePolicy {
+	return CachePolicy{
+		Transaction:  24 * time.Hour,
+		Status:       time.Minute,
+		Transactions: 24 * time.Hour,
+	}
+}
+
+// CacheStats are numbers of cacheable requests which were served from cache and which were not
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// responseCache is cache used by the client together with its statistics
+type responseCache struct {
+	store  Cache
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+// WithCache caches responses in given cache according to client cache policy (DefaultCachePolicy unless set)
+// Every call of WithCache starts new statistics
+func WithCache(cache Cache) Option {
+	return func(api *WhaleAlertAPI) {
+		if cache == nil {
+			api.cache = nil
+			return
+		}
+		api.cache = &responseCache{store: cache}
+	}
+}
+
+// WithCachePolicy sets how long responses of each endpoint are cached
+func WithCachePolicy(policy CachePolicy) Option {
+	return func(api *WhaleAlertAPI) {
+		api.cachePolicy = policy
+	}
+}
+
+// CacheStats returns statistics of client cache
+func (api WhaleAlertAPI) CacheStats() CacheStats {
+	if api.cache == nil {
+		return CacheStats{}
+	}
+	return CacheStats{Hits: api.cache.hits.Load(), Misses: api.cache.misses.Load()}
+}
+
+// cacheTTL returns how long response of request may be cached, zero when it must not be cached
+func (api WhaleAlertAPI) cacheTTL(method, endpoint string, args []APIArgument) time.Duration {
+	if api.cache == nil || method != http.MethodGet {
+		return 0
+	}
+	switch {
+	case endpoint == "/status":
+		return api.cachePolicy.Status
+	case strings.HasPrefix(endpoint, "/transaction/"):
+		return api.cachePolicy.Transaction
+	case endpoint == "/transactions":
+		for _, arg := range args {
+			if arg.Key != "end" {
+				continue
+			}
+			end, err := strconv.ParseInt(arg.Value, 10, 64)
+			if err == nil && end > 0 && time.Unix(end, 0).Before(time.Now()) {
+				return api.cachePolicy.Transactions
+			}
+		}
+	}
+	return 0
+}
+
+// cacheableBody returns false for responses which may change later
+// Transaction which is not indexed yet is returned without transactions, so such response is not cached
+func cacheableBody(endpoint string, body []byte) bool {
+	if !strings.HasPrefix(endpoint, "/transaction/") {
+		return true
+	}
+	var res struct {
+		Transactions []json.RawMessage `json:"transactions"`
+	}
+	return json.Unmarshal(body, &res) == nil && len(res.Transactions) > 0
+}
+
+func (c *responseCache) get(key string) ([]byte, bool) {
+	value, ok := c.store.Get(key)
+	if ok {
+		c.hits.Add(1)
+	} else {
+		c.misses.Add(1)
+	}
+	return value, ok
+}
+
+func (c *responseCache) set(key string, value []byte, ttl time.Duration) {
+	c.store.Set(key, value, ttl)
+}
+
+// MemoryCache is Cache keeping limited number of entries in memory, least recently used entries are evicted first
+type MemoryCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	order   *list.List
+	now     func() time.Time
+}
+
+type memoryCacheEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// NewMemoryCache returns memory cache keeping at most size entries, DefaultCacheSize is used when size is not positive
+func NewMemoryCache(size int) *MemoryCache {
+	if size <= 0 {
+		size = DefaultCacheSize
+	}
+	return &MemoryCache{
+		size:    size,
+		entries: map[string]*list.Element{},
+		order:   list.New(),
+		now:     time.Now,
+	}
+}
+
+func (c *MemoryCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*memoryCacheEntry)
+	if !c.now().Before(entry.expires) {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return entry.value, true
+}
+
+func (c *MemoryCache) Set(key string, value []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry := &memoryCacheEntry{key: key, value: value, expires: c.now().Add(ttl)}
+	if element, ok := c.entries[key]; ok {
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.order.PushFront(entry)
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*memoryCacheEntry).key)
+	}
+}
+
+// Len returns number of entries in the cache, including expired ones which were not evicted yet
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// DiskCache is Cache keeping every entry in separate file of a directory
+// Entries are written atomically, so the cache may be shared by many processes
+type DiskCache struct {
+	dir string
+	now func() time.Time
+}
+
+type diskCacheEntry struct {
+	Expires time.Time `json:"expires"`
+	Value   []byte    `json:"value"`
+}
+
+// NewDiskCache returns cache keeping entries in dir, dir is created when it doesn't exist
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &DiskCache{dir: dir, now: time.Now}, nil
+}
+
+// Get returns value stored with key, unreadable entries are treated as missing
+func (c *DiskCache) Get(key string) ([]byte, bool) {
+	path := c.path(key)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var entry diskCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false
+	}
+	if !c.now().Before(entry.Expires) {
+		os.Remove(path)
+		return nil, false
+	}
+	return entry.Value, true
+}
+
+// Set stores value with key, errors are ignored as the entry is only missing from cache then
+func (c *DiskCache) Set(key string, value []byte, ttl time.Duration) {
+	data, err := json.Marshal(diskCacheEntry{Expires: c.now().Add(ttl), Value: value})
+	if err != nil {
+		return
+	}
+	writeFileAtomic(c.path(key), data)
+}
+
+// path returns file of key entry, key is hashed as urls are not valid file names
+func (c *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
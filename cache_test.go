@@ -0,0 +1,130 @@
+package whalealertapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMemoryCache(t *testing.T) {
+	now := time.Date(2023, 3, 25, 12, 0, 0, 0, time.UTC)
+	c := NewMemoryCache(2)
+	c.now = func() time.Time { return now }
+
+	c.Set("a", []byte("1"), time.Minute)
+	c.Set("b", []byte("2"), time.Hour)
+	if v, ok := c.Get("a"); !ok || string(v) != "1" {
+		t.Errorf("Expected %s, got %s %t", "1", v, ok)
+	}
+	// b is least recently used, so it is evicted
+	c.Set("c", []byte("3"), time.Hour)
+	if _, ok := c.Get("b"); ok {
+		t.Errorf("Expected b to be evicted")
+	}
+	if c.Len() != 2 {
+		t.Errorf("Expected %d entries, got %d", 2, c.Len())
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok := c.Get("a"); ok {
+		t.Errorf("Expected a to expire")
+	}
+	if v, ok := c.Get("c"); !ok || string(v) != "3" {
+		t.Errorf("Expected %s, got %s %t", "3", v, ok)
+	}
+}
+
+func TestDiskCache(t *testing.T) {
+	now := time.Date(2023, 3, 25, 12, 0, 0, 0, time.UTC)
+	c, err := NewDiskCache(t.TempDir() + "/cache")
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err)
+	}
+	c.now = func() time.Time { return now }
+
+	key := "GET https://api.whale-alert.io/v1/transaction/bitcoin/abc"
+	c.Set(key, []byte(`{"result":"success"}`), time.Minute)
+	if v, ok := c.Get(key); !ok || string(v) != `{"result":"success"}` {
+		t.Errorf("Expected cached value, got %s %t", v, ok)
+	}
+	if _, ok := c.Get("other"); ok {
+		t.Errorf("Expected missing value")
+	}
+	now = now.Add(time.Minute)
+	if _, ok := c.Get(key); ok {
+		t.Errorf("Expected value to expire")
+	}
+}
+
+func TestCachedRequests(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		switch r.URL.Path {
+		case "/status":
+			w.Write([]byte(`{"result":"success","blockchain_count":1}`))
+		case "/transactions":
+			w.Write([]byte(`{"result":"success","count":0,"cursor":"0-0-0"}`))
+		case "/transaction/bitcoin/missing":
+			w.Write([]byte(`{"result":"success","count":0}`))
+		default:
+			w.Write([]byte(`{"result":"success","count":1,"transactions":[{"blockchain":"bitcoin","hash":"abc"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	api := New(WithURL(server.URL), WithAccessKey("X"), WithCache(NewMemoryCache(0)), WithValidation(false))
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		res, meta, err := api.TransactionWithMeta(ctx, "bitcoin", "abc")
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err)
+		}
+		if res.Transactions[0].Hash != "abc" || meta.Cached != (i > 0) {
+			t.Errorf("Unexpected response %+v %+v", res, meta)
+		}
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected %d calls, got %d", 1, calls.Load())
+	}
+	if stats := api.CacheStats(); stats.Hits != 2 || stats.Misses != 1 {
+		t.Errorf("Expected %d hits and %d misses, got %+v", 2, 1, stats)
+	}
+
+	// Transaction which is not indexed yet is not cached
+	calls.Store(0)
+	for i := 0; i < 2; i++ {
+		res, err := api.Transaction("bitcoin", "missing")
+		if err != nil || res.Count != 0 {
+			t.Errorf("Expected empty response, got %+v %v", res, err)
+		}
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Expected %d calls, got %d", 2, calls.Load())
+	}
+
+	// Only windows which are fully in the past are cached
+	calls.Store(0)
+	past := uint(time.Now().Add(-time.Hour).Unix())
+	for i := 0; i < 2; i++ {
+		api.Transactions(past, TransactionsRequest{End: past + 60})
+		api.Transactions(past, TransactionsRequest{})
+		api.Transactions(past, TransactionsRequest{End: uint(time.Now().Add(time.Hour).Unix())})
+	}
+	if calls.Load() != 5 {
+		t.Errorf("Expected %d calls, got %d", 5, calls.Load())
+	}
+
+	// Policy disables caching of status
+	calls.Store(0)
+	noStatus := api.With(WithCachePolicy(CachePolicy{Transaction: time.Hour}))
+	noStatus.Status()
+	noStatus.Status()
+	if calls.Load() != 2 {
+		t.Errorf("Expected %d calls, got %d", 2, calls.Load())
+	}
+}
@@ -0,0 +1,216 @@
+package whalealertapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCatalogTTL is how long blockchain catalog uses status before refreshing it
+const DefaultCatalogTTL = 10 * time.Minute
+
+var (
+	ErrUnsupportedBlockchain  error = errors.New("unsupported blockchain")
+	ErrBlockchainDisconnected error = errors.New("blockchain is disconnected")
+)
+
+// catalogRetryDelay is how long catalog waits before it calls /status again after failed refresh
+const catalogRetryDelay = 30 * time.Second
+
+// BlockchainCatalog keeps blockchains reported by /status endpoint and refreshes them when they are older than ttl
+// It is safe for concurrent use, concurrent callers share one refresh
+type BlockchainCatalog struct {
+	api WhaleAlertAPI
+	ttl time.Duration
+	now func() time.Time
+
+	mu          sync.Mutex
+	blockchains map[BlockchainName]Blockchain
+	updated     time.Time
+	// failed is time of the last failed refresh and err its error, zero after successful refresh
+	failed     time.Time
+	err        error
+	refreshing *catalogRefresh
+}
+
+// catalogRefresh is refresh in progress, err is set before done is closed
+type catalogRefresh struct {
+	done chan struct{}
+	err  error
+}
+
+// NewBlockchainCatalog returns catalog of blockchains which calls /status with this client
+// DefaultCatalogTTL is used when ttl is not positive
+func (api WhaleAlertAPI) NewBlockchainCatalog(ttl time.Duration) *BlockchainCatalog {
+	if ttl <= 0 {
+		ttl = DefaultCatalogTTL
+	}
+	// Catalog refreshes itself with plain status calls, not through a guarded client
+	api.catalog = nil
+	return &BlockchainCatalog{api: api, ttl: ttl, now: time.Now}
+}
+
+// WithBlockchainCatalog makes Transaction check blockchain in catalog before calling the API
+// Calls for unsupported or disconnected blockchains fail without spending a request
+func WithBlockchainCatalog(catalog *BlockchainCatalog) Option {
+	return func(api *WhaleAlertAPI) {
+		api.catalog = catalog
+	}
+}
+
+// Refresh loads blockchains from /status endpoint, even when cached ones are not expired
+// Refresh continues when ctx is done, so other callers waiting for it still get its result
+func (c *BlockchainCatalog) Refresh(ctx context.Context) error {
+	c.mu.Lock()
+	r := c.startRefresh(ctx)
+	c.mu.Unlock()
+	return r.wait(ctx)
+}
+
+// startRefresh returns refresh in progress or starts a new one, it must be called with mu held
+func (c *BlockchainCatalog) startRefresh(ctx context.Context) *catalogRefresh {
+	if c.refreshing != nil {
+		return c.refreshing
+	}
+	r := &catalogRefresh{done: make(chan struct{})}
+	c.refreshing = r
+	go c.refresh(context.WithoutCancel(ctx), r)
+	return r
+}
+
+// refresh calls /status without holding mu and stores the result
+func (c *BlockchainCatalog) refresh(ctx context.Context, r *catalogRefresh) {
+	status, err := c.api.StatusContext(ctx)
+	c.mu.Lock()
+	if err != nil {
+		c.failed, c.err = c.now(), err
+	} else {
+		blockchains := make(map[BlockchainName]Blockchain, len(status.Blockchains))
+		for _, b := range status.Blockchains {
+			blockchains[normalizeBlockchain(b.Name)] = b
+		}
+		c.blockchains, c.updated = blockchains, c.now()
+		c.failed, c.err = time.Time{}, nil
+	}
+	c.refreshing = nil
+	c.mu.Unlock()
+	r.err = err
+	close(r.done)
+}
+
+// wait waits until refresh is done or ctx is done
+func (r *catalogRefresh) wait(ctx context.Context) error {
+	select {
+	case <-r.done:
+		return r.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// retryDelay is how long to wait after failed refresh, it is never longer than ttl
+func (c *BlockchainCatalog) retryDelay() time.Duration {
+	return min(c.ttl, catalogRetryDelay)
+}
+
+// load returns blockchains, refreshing them when expired
+// When refresh fails, expired blockchains are used, error is returned only when nothing was loaded yet
+// After failed refresh, /status is not called again for a while, the failure is reported instead
+func (c *BlockchainCatalog) load(ctx context.Context) (map[BlockchainName]Blockchain, error) {
+	c.mu.Lock()
+	now := c.now()
+	if c.blockchains != nil && now.Sub(c.updated) < c.ttl {
+		defer c.mu.Unlock()
+		return c.blockchains, nil
+	}
+	if !c.failed.IsZero() && now.Sub(c.failed) < c.retryDelay() {
+		defer c.mu.Unlock()
+		if c.blockchains == nil {
+			return nil, c.err
+		}
+		return c.blockchains, nil
+	}
+	r := c.startRefresh(ctx)
+	c.mu.Unlock()
+
+	err := r.wait(ctx)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.blockchains == nil {
+		return nil, err
+	}
+	return c.blockchains, nil
+}
+
+// Blockchains returns all blockchains sorted by name
+func (c *BlockchainCatalog) Blockchains(ctx context.Context) ([]Blockchain, error) {
+	blockchains, err := c.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]Blockchain, 0, len(blockchains))
+	for _, b := range blockchains {
+		res = append(res, b)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res, nil
+}
+
+// Lookup returns blockchain with given name
+// Error wraps ErrUnsupportedBlockchain when blockchain is unknown and ErrBlockchainDisconnected when it is not connected
+func (c *BlockchainCatalog) Lookup(ctx context.Context, chain BlockchainName) (Blockchain, error) {
+	blockchains, err := c.load(ctx)
+	if err != nil {
+		return Blockchain{}, err
+	}
+	b, ok := blockchains[normalizeBlockchain(chain)]
+	if !ok {
+		return Blockchain{}, fmt.Errorf("%w: %s", ErrUnsupportedBlockchain, chain)
+	}
+	if b.Status != BlockchainConnected {
+		return b, fmt.Errorf("%w: %s", ErrBlockchainDisconnected, chain)
+	}
+	return b, nil
+}
+
+// SupportsSymbol returns true when blockchain is known and reports given symbol, symbols are compared case insensitively
+func (c *BlockchainCatalog) SupportsSymbol(ctx context.Context, chain BlockchainName, symbol string) (bool, error) {
+	blockchains, err := c.load(ctx)
+	if err != nil {
+		return false, err
+	}
+	b, ok := blockchains[normalizeBlockchain(chain)]
+	return ok && hasSymbol(b, symbol), nil
+}
+
+// ChainsForSymbol returns sorted names of blockchains which report given symbol
+func (c *BlockchainCatalog) ChainsForSymbol(ctx context.Context, symbol string) ([]BlockchainName, error) {
+	blockchains, err := c.Blockchains(ctx)
+	if err != nil {
+		return nil, err
+	}
+	chains := []BlockchainName{}
+	for _, b := range blockchains {
+		if hasSymbol(b, symbol) {
+			chains = append(chains, b.Name)
+		}
+	}
+	return chains, nil
+}
+
+func hasSymbol(b Blockchain, symbol string) bool {
+	for _, s := range b.Symbols {
+		if s != "" && strings.EqualFold(s, symbol) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeBlockchain(chain BlockchainName) BlockchainName {
+	return BlockchainName(strings.ToLower(string(chain)))
+}
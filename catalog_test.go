@@ -0,0 +1,153 @@
+package whalealertapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBlockchainCatalog(t *testing.T) {
+	var statusCalls, transactionCalls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/status" {
+			statusCalls.Add(1)
+			w.Write([]byte(`{"result":"success","blockchain_count":3,"blockchains":[` +
+				`{"name":"Ethereum","symbols":["eth","usdt"],"status":"connected"},` +
+				`{"name":"tron","symbols":["usdt","trx"],"status":"connected"},` +
+				`{"name":"ripple","symbols":["","xrp"],"status":"disconnected"}]}`))
+			return
+		}
+		transactionCalls.Add(1)
+		w.Write([]byte(`{"result":"success","count":1,"transactions":[{"blockchain":"tron","hash":"abc"}]}`))
+	}))
+	defer server.Close()
+
+	now := time.Date(2023, 3, 25, 12, 0, 0, 0, time.UTC)
+	api := New(WithURL(server.URL), WithAccessKey("X"))
+	catalog := api.NewBlockchainCatalog(time.Minute)
+	catalog.now = func() time.Time { return now }
+	guarded := api.With(WithBlockchainCatalog(catalog))
+	ctx := context.Background()
+
+	if _, err := guarded.Transaction("tron", "abc"); err != nil {
+		t.Errorf("Expected nil, got %s", err)
+	}
+	if _, err := guarded.Transaction("dogecoin", "abc"); !errors.Is(err, ErrUnsupportedBlockchain) {
+		t.Errorf("Expected %s, got %v", ErrUnsupportedBlockchain, err)
+	}
+	if _, err := guarded.Transaction("ripple", "abc"); !errors.Is(err, ErrBlockchainDisconnected) {
+		t.Errorf("Expected %s, got %v", ErrBlockchainDisconnected, err)
+	}
+	if statusCalls.Load() != 1 || transactionCalls.Load() != 1 {
+		t.Errorf("Expected %d status and %d transaction calls, got %d and %d", 1, 1, statusCalls.Load(), transactionCalls.Load())
+	}
+
+	if ok, _ := catalog.SupportsSymbol(ctx, "ethereum", "USDT"); !ok {
+		t.Errorf("Expected ethereum to support usdt")
+	}
+	if ok, _ := catalog.SupportsSymbol(ctx, "ripple", ""); ok {
+		t.Errorf("Expected empty symbol not to be supported")
+	}
+	chains, err := catalog.ChainsForSymbol(ctx, "usdt")
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err)
+	}
+	if want := []BlockchainName{BlockchainEthereum, BlockchainTron}; !reflect.DeepEqual(chains, want) {
+		t.Errorf("Expected %v, got %v", want, chains)
+	}
+
+	// Status is refreshed after ttl
+	now = now.Add(time.Minute)
+	catalog.Lookup(ctx, BlockchainTron)
+	if statusCalls.Load() != 2 {
+		t.Errorf("Expected %d status calls, got %d", 2, statusCalls.Load())
+	}
+
+	// Expired blockchains are used when refresh fails
+	now = now.Add(time.Minute)
+	catalog.api = *api.With(WithAccessKey(""))
+	if _, err := catalog.Lookup(ctx, BlockchainTron); err != nil {
+		t.Errorf("Expected nil, got %s", err)
+	}
+	empty := New(WithURL(server.URL)).NewBlockchainCatalog(0)
+	if _, err := empty.Lookup(ctx, BlockchainTron); !errors.Is(err, ErrMissingAccessKey) {
+		t.Errorf("Expected %s, got %v", ErrMissingAccessKey, err)
+	}
+}
+
+func TestBlockchainCatalogFailedRefresh(t *testing.T) {
+	var statusCalls atomic.Int32
+	var failing atomic.Bool
+	failing.Store(true)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		statusCalls.Add(1)
+		<-release
+		if failing.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"result":"error","message":"unavailable"}`))
+			return
+		}
+		w.Write([]byte(`{"result":"success","blockchain_count":1,"blockchains":[{"name":"tron","symbols":["trx"],"status":"connected"}]}`))
+	}))
+	defer server.Close()
+	defer close(release)
+
+	now := time.Date(2023, 3, 25, 12, 0, 0, 0, time.UTC)
+	var mu sync.Mutex
+	catalog := New(WithURL(server.URL), WithAccessKey("X")).NewBlockchainCatalog(time.Hour)
+	catalog.now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	ctx := context.Background()
+
+	// Concurrent lookups share one refresh
+	errs := make(chan error, 5)
+	for i := 0; i < 5; i++ {
+		go func() {
+			_, err := catalog.Lookup(ctx, BlockchainTron)
+			errs <- err
+		}()
+	}
+	for i := 0; i < 1000 && statusCalls.Load() == 0; i++ {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+	release <- struct{}{}
+	for i := 0; i < 5; i++ {
+		if err := <-errs; !errors.Is(err, ErrServerError) {
+			t.Errorf("Expected %s, got %v", ErrServerError, err)
+		}
+	}
+	if statusCalls.Load() != 1 {
+		t.Errorf("Expected %d status call, got %d", 1, statusCalls.Load())
+	}
+
+	// Failure is reported without calling /status until retry delay passes
+	if _, err := catalog.Lookup(ctx, BlockchainTron); !errors.Is(err, ErrServerError) {
+		t.Errorf("Expected %s, got %v", ErrServerError, err)
+	}
+	if statusCalls.Load() != 1 {
+		t.Errorf("Expected %d status call, got %d", 1, statusCalls.Load())
+	}
+
+	mu.Lock()
+	now = now.Add(catalogRetryDelay)
+	mu.Unlock()
+	failing.Store(false)
+	go func() { release <- struct{}{} }()
+	if _, err := catalog.Lookup(ctx, BlockchainTron); err != nil {
+		t.Errorf("Expected nil, got %s", err)
+	}
+	if statusCalls.Load() != 2 {
+		t.Errorf("Expected %d status calls, got %d", 2, statusCalls.Load())
+	}
+}
@@ -0,0 +1,141 @@
+package whalealertapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Checkpoint keeps position in /transactions stream
+type Checkpoint struct {
+	// Timestamp is start which should be used by next request
+	Timestamp uint   `json:"timestamp"`
+	Cursor    string `json:"cursor,omitempty"`
+	// SeenIDs are IDs of recently delivered transactions which can be returned again
+	SeenIDs   []string  `json:"seen_ids,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CheckpointStore loads and saves checkpoints, so Watcher and Backfiller can resume after restart
+type CheckpointStore interface {
+	// Load returns last saved checkpoint, or nil when nothing was saved yet
+	Load(ctx context.Context) (*Checkpoint, error)
+	// Save replaces saved checkpoint
+	Save(ctx context.Context, checkpoint Checkpoint) error
+}
+
+// MemoryCheckpointStore keeps checkpoint in memory, it is safe for concurrent use
+type MemoryCheckpointStore struct {
+	mu         sync.Mutex
+	checkpoint *Checkpoint
+}
+
+// NewMemoryCheckpointStore returns empty MemoryCheckpointStore
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{}
+}
+
+func (s *MemoryCheckpointStore) Load(ctx context.Context) (*Checkpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.checkpoint == nil {
+		return nil, nil
+	}
+	checkpoint := s.checkpoint.clone()
+	return &checkpoint, nil
+}
+
+func (s *MemoryCheckpointStore) Save(ctx context.Context, checkpoint Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	checkpoint = checkpoint.clone()
+	s.checkpoint = &checkpoint
+	return nil
+}
+
+// FileCheckpointStore keeps checkpoint in JSON file
+// File is replaced atomically: checkpoint is written to temporary file which is renamed,
+// so the file is never left half written when process crashes
+type FileCheckpointStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileCheckpointStore returns store which keeps checkpoint in file with given path
+func NewFileCheckpointStore(path string) *FileCheckpointStore {
+	return &FileCheckpointStore{path: path}
+}
+
+func (s *FileCheckpointStore) Load(ctx context.Context) (*Checkpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var checkpoint Checkpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, err
+	}
+	return &checkpoint, nil
+}
+
+func (s *FileCheckpointStore) Save(ctx context.Context, checkpoint Checkpoint) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return writeFileAtomic(s.path, data)
+}
+
+// writeFileAtomic replaces file at path with data, file is written to temporary file and renamed,
+// so readers never see partially written file
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	// Sync directory so rename survives power loss, not supported on every platform
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+	return nil
+}
+
+// clone returns deep copy of checkpoint
+func (c Checkpoint) clone() Checkpoint {
+	if c.SeenIDs != nil {
+		c.SeenIDs = append([]string{}, c.SeenIDs...)
+	}
+	return c
+}
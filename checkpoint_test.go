@@ -0,0 +1,143 @@
+package whalealertapi_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	whalealertapi "github.com/devbay-io/whale_alert_api_client"
+	"github.com/devbay-io/whale_alert_api_client/whalealerttest"
+)
+
+func testCheckpointStore(t *testing.T, store whalealertapi.CheckpointStore) {
+	t.Helper()
+	ctx := context.Background()
+	checkpoint, err := store.Load(ctx)
+	if err != nil || checkpoint != nil {
+		t.Fatalf("Expected (nil, nil), got (%v, %v)", checkpoint, err)
+	}
+
+	saved := whalealertapi.Checkpoint{Timestamp: 1679774508, Cursor: "76a63333-76a63333-641f534f", SeenIDs: []string{"1", "2"}}
+	if err := store.Save(ctx, saved); err != nil {
+		t.Fatalf("Expected nil, got %s", err)
+	}
+	saved.SeenIDs[0] = "changed"
+	checkpoint, err = store.Load(ctx)
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err)
+	}
+	if checkpoint.Timestamp != 1679774508 || checkpoint.Cursor != "76a63333-76a63333-641f534f" {
+		t.Errorf("Unexpected checkpoint %+v", checkpoint)
+	}
+	if len(checkpoint.SeenIDs) != 2 || checkpoint.SeenIDs[0] != "1" {
+		t.Errorf("Expected seen IDs [1 2], got %v", checkpoint.SeenIDs)
+	}
+
+	if err := store.Save(ctx, whalealertapi.Checkpoint{Timestamp: 1679774600}); err != nil {
+		t.Fatalf("Expected nil, got %s", err)
+	}
+	checkpoint, err = store.Load(ctx)
+	if err != nil || checkpoint.Timestamp != 1679774600 || len(checkpoint.SeenIDs) != 0 {
+		t.Errorf("Expected overwritten checkpoint, got (%+v, %v)", checkpoint, err)
+	}
+}
+
+func TestMemoryCheckpointStore(t *testing.T) {
+	testCheckpointStore(t, whalealertapi.NewMemoryCheckpointStore())
+}
+
+func TestFileCheckpointStore(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "checkpoint.json")
+	testCheckpointStore(t, whalealertapi.NewFileCheckpointStore(path))
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "checkpoint.json" {
+		t.Errorf("Expected only checkpoint file, got %v", entries)
+	}
+
+	// Leftover of interrupted write does not affect saved checkpoint
+	os.WriteFile(filepath.Join(dir, "checkpoint.json.tmp-123"), []byte(`{"timest`), 0o600)
+	checkpoint, err := whalealertapi.NewFileCheckpointStore(path).Load(context.Background())
+	if err != nil || checkpoint.Timestamp != 1679774600 {
+		t.Errorf("Expected saved checkpoint, got (%+v, %v)", checkpoint, err)
+	}
+
+	os.WriteFile(path, []byte(`{"timest`), 0o600)
+	if _, err := whalealertapi.NewFileCheckpointStore(path).Load(context.Background()); err == nil {
+		t.Errorf("Expected error for corrupted file")
+	}
+}
+
+func TestWatcherResumesFromCheckpoint(t *testing.T) {
+	srv := whalealerttest.NewServer().AddTransactions(
+		whalealertapi.Transaction{Blockchain: "ethereum", ID: "1", Timestamp: 1679774501},
+		whalealertapi.Transaction{Blockchain: "ethereum", ID: "2", Timestamp: 1679774502},
+	)
+	defer srv.Close()
+	store := whalealertapi.NewMemoryCheckpointStore()
+
+	watch := func() (<-chan whalealertapi.Transaction, context.CancelFunc) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		t.Cleanup(cancel)
+		transactions, _ := srv.Client().NewWatcher(whalealertapi.TransactionsRequest{Start: 1679774500}).
+			WithInterval(10 * time.Millisecond).
+			WithCheckpointStore(store).
+			Run(ctx)
+		return transactions, cancel
+	}
+
+	transactions, stop := watch()
+	for _, id := range []string{"1", "2"} {
+		if tx := <-transactions; tx.ID != id {
+			t.Errorf("Expected %s, got %s", id, tx.ID)
+		}
+	}
+	// Wait until the position is committed
+	for {
+		checkpoint, _ := store.Load(context.Background())
+		if checkpoint != nil && checkpoint.Timestamp == 1679774501 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	stop()
+	for range transactions {
+	}
+
+	srv.AddTransactions(whalealertapi.Transaction{Blockchain: "ethereum", ID: "3", Timestamp: 1679774502})
+	transactions, _ = watch()
+	if tx := <-transactions; tx.ID != "3" {
+		t.Errorf("Expected %s, got %s", "3", tx.ID)
+	}
+}
+
+func TestBackfillResumesFromCheckpoint(t *testing.T) {
+	srv := whalealerttest.NewServer().AddTransactions(
+		whalealertapi.Transaction{Blockchain: "ethereum", ID: "1", Timestamp: 1679770100},
+		whalealertapi.Transaction{Blockchain: "ethereum", ID: "2", Timestamp: 1679774000},
+	)
+	defer srv.Close()
+
+	store := whalealertapi.NewMemoryCheckpointStore()
+	store.Save(context.Background(), whalealertapi.Checkpoint{Timestamp: 1679771800})
+	res, err := srv.Client().NewBackfiller(1679770000, 1679777000, whalealertapi.TransactionsRequest{}).
+		WithWindow(30 * time.Minute).
+		WithCheckpointStore(store).
+		Run(context.Background())
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err)
+	}
+	if len(res) != 1 || res[0].ID != "2" {
+		t.Errorf("Expected only transaction 2, got %v", res)
+	}
+	checkpoint, _ := store.Load(context.Background())
+	if checkpoint.Timestamp != 1679777000 {
+		t.Errorf("Expected checkpoint at %d, got %d", 1679777000, checkpoint.Timestamp)
+	}
+}
@@ -0,0 +1,88 @@
+package whalealertapi
+
+import (
+	"context"
+	"sync"
+)
+
+// flightGroup shares one in-flight request between identical concurrent calls
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flight
+}
+
+// flight is request shared by waiting callers, its fields are set before done is closed
+type flight struct {
+	done    chan struct{}
+	cancel  context.CancelFunc
+	waiters int
+	body    []byte
+	meta    *ResponseMeta
+	err     error
+}
+
+// WithCoalescing makes identical concurrent requests share one in-flight request, it is disabled by default
+// Only GET and HEAD requests are coalesced, they are identical when they have the same method, url with query and access key
+// All callers receive result of the shared request, each one decodes its own copy of it
+// Shared request is cancelled only when contexts of all waiting callers are done
+func WithCoalescing(enabled bool) Option {
+	return func(api *WhaleAlertAPI) {
+		if !enabled {
+			api.flights = nil
+			return
+		}
+		api.flights = &flightGroup{calls: map[string]*flight{}}
+	}
+}
+
+// do calls fn once for concurrent calls with the same key and returns its result to all of them
+// fn gets context which keeps values of ctx of the first caller but is not cancelled with it
+func (g *flightGroup) do(ctx context.Context, key string, fn func(context.Context) ([]byte, *ResponseMeta, error)) ([]byte, *ResponseMeta, error) {
+	g.mu.Lock()
+	f, ok := g.calls[key]
+	if !ok {
+		sharedCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		f = &flight{done: make(chan struct{}), cancel: cancel}
+		g.calls[key] = f
+		go func() {
+			f.body, f.meta, f.err = fn(sharedCtx)
+			close(f.done)
+			cancel()
+			g.forget(key, f)
+		}()
+	}
+	f.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-f.done:
+		if f.meta == nil {
+			return f.body, nil, f.err
+		}
+		meta := *f.meta
+		return f.body, &meta, f.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		f.waiters--
+		if f.waiters == 0 {
+			f.cancel()
+			g.remove(key, f)
+		}
+		g.mu.Unlock()
+		return nil, nil, ctx.Err()
+	}
+}
+
+// forget removes finished flight, so next call sends new request
+func (g *flightGroup) forget(key string, f *flight) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.remove(key, f)
+}
+
+// remove must be called with mu held
+func (g *flightGroup) remove(key string, f *flight) {
+	if g.calls[key] == f {
+		delete(g.calls, key)
+	}
+}
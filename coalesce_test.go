@@ -0,0 +1,132 @@
+package whalealertapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitForWaiters waits until n callers wait for the only in-flight request
+func waitForWaiters(t *testing.T, g *flightGroup, n int) {
+	t.Helper()
+	for i := 0; i < 1000; i++ {
+		g.mu.Lock()
+		waiters := 0
+		for _, f := range g.calls {
+			waiters = f.waiters
+		}
+		g.mu.Unlock()
+		if waiters == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("Expected %d waiting callers", n)
+}
+
+func TestCoalescing(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	cancelled := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		select {
+		case <-release:
+		case <-r.Context().Done():
+			cancelled <- struct{}{}
+			return
+		}
+		w.Write([]byte(`{"result":"success","count":1,"transactions":[{"blockchain":"bitcoin","hash":"abc"}]}`))
+	}))
+	defer server.Close()
+
+	api := New(WithURL(server.URL), WithAccessKey("X"), WithCoalescing(true))
+
+	results := make([]*TransactionResponse, 10)
+	errs := make([]error, 10)
+	wg := sync.WaitGroup{}
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = api.Transaction("bitcoin", "abc")
+		}(i)
+	}
+	waitForWaiters(t, api.flights, 10)
+	release <- struct{}{}
+	wg.Wait()
+	if calls.Load() != 1 {
+		t.Errorf("Expected %d call, got %d", 1, calls.Load())
+	}
+	for i := range results {
+		if errs[i] != nil || results[i].Transactions[0].Hash != "abc" {
+			t.Errorf("Expected transaction abc, got %v %v", results[i], errs[i])
+		}
+		if i > 0 && results[i] == results[0] {
+			t.Errorf("Expected every caller to get its own result")
+		}
+	}
+
+	// POST requests are never coalesced
+	calls.Store(0)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			doWithMeta[TransactionResponse](context.Background(), *api, http.MethodPost, "/transaction/bitcoin/abc", nil)
+		}()
+	}
+	for i := 0; i < 1000 && calls.Load() != 5; i++ {
+		time.Sleep(time.Millisecond)
+	}
+	if calls.Load() != 5 {
+		t.Errorf("Expected %d calls, got %d", 5, calls.Load())
+	}
+	for i := int32(0); i < calls.Load(); i++ {
+		release <- struct{}{}
+	}
+	wg.Wait()
+
+	// Cancelled caller doesn't cancel request shared with others
+	calls.Store(0)
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := api.TransactionContext(ctx, "bitcoin", "abc")
+		first <- err
+	}()
+	waitForWaiters(t, api.flights, 1)
+	second := make(chan error, 1)
+	go func() {
+		_, err := api.TransactionContext(context.Background(), "bitcoin", "abc")
+		second <- err
+	}()
+	waitForWaiters(t, api.flights, 2)
+	cancel()
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected %s, got %v", context.Canceled, err)
+	}
+	release <- struct{}{}
+	if err := <-second; err != nil {
+		t.Errorf("Expected nil, got %s", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected %d call, got %d", 1, calls.Load())
+	}
+
+	// Request is cancelled when nobody waits for it
+	ctx, cancel = context.WithCancel(context.Background())
+	go api.TransactionContext(ctx, "bitcoin", "abc")
+	waitForWaiters(t, api.flights, 1)
+	cancel()
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Errorf("Expected shared request to be cancelled")
+	}
+}
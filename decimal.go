@@ -0,0 +1,246 @@
+package whalealertapi
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// maxDecimalExponent and maxDecimalScale bound parsed values, so hostile input can't make
+// arithmetic build numbers with millions of digits
+const (
+	maxDecimalExponent = 1000
+	maxDecimalScale    = 1000
+)
+
+// Decimal is exact decimal number, decoded straight from JSON number text
+// Its value is unscaled * 10^-scale. Zero value is 0, Decimal is immutable and safe to copy.
+// Decimal is comparable, == is true for the same value with the same scale, use Cmp to ignore scale
+type Decimal struct {
+	// unscaled is canonical base 10 text of unscaled value, empty for 0
+	unscaled string
+	scale    int32
+}
+
+// newDecimal returns Decimal with given unscaled value and scale
+func newDecimal(unscaled *big.Int, scale int32) Decimal {
+	if unscaled.Sign() == 0 {
+		return Decimal{scale: scale}
+	}
+	return Decimal{unscaled: unscaled.String(), scale: scale}
+}
+
+// NewDecimalFromInt returns Decimal equal to v
+func NewDecimalFromInt(v int64) Decimal {
+	return newDecimal(big.NewInt(v), 0)
+}
+
+// NewDecimalFromFloat returns Decimal with shortest representation of f
+func NewDecimalFromFloat(f float64) Decimal {
+	d, err := ParseDecimal(strconv.FormatFloat(f, 'f', -1, 64))
+	if err != nil {
+		return Decimal{}
+	}
+	return d
+}
+
+// ParseDecimal parses decimal number like "824064.6", "-5" or "1.5e6"
+// Exponent and number of fraction digits are limited to 1000
+func ParseDecimal(s string) (Decimal, error) {
+	invalid := fmt.Errorf("invalid decimal %q", s)
+	mantissa, exponent := s, int64(0)
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		e, err := strconv.ParseInt(s[i+1:], 10, 32)
+		if err != nil || e > maxDecimalExponent || e < -maxDecimalExponent {
+			return Decimal{}, invalid
+		}
+		mantissa, exponent = s[:i], e
+	}
+	sign := ""
+	if strings.HasPrefix(mantissa, "-") || strings.HasPrefix(mantissa, "+") {
+		sign, mantissa = mantissa[:1], mantissa[1:]
+	}
+	integer, fraction, _ := strings.Cut(mantissa, ".")
+	digits := integer + fraction
+	if digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return Decimal{}, invalid
+	}
+	unscaled, ok := new(big.Int).SetString(sign+digits, 10)
+	if !ok {
+		return Decimal{}, invalid
+	}
+	scale := int64(len(fraction)) - exponent
+	if scale > maxDecimalScale {
+		return Decimal{}, invalid
+	}
+	if scale < 0 {
+		unscaled.Mul(unscaled, pow10(-scale))
+		scale = 0
+	}
+	return newDecimal(unscaled, int32(scale)), nil
+}
+
+// MustParseDecimal is ParseDecimal which panics on error, it is meant for constants
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// UnmarshalJSON decodes JSON number (or string with number) without losing precision
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	parsed, err := ParseDecimal(strings.Trim(string(data), `"`))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// MarshalJSON encodes Decimal as JSON number
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// String returns d in plain decimal notation, keeping its scale
+func (d Decimal) String() string {
+	digits := new(big.Int).Abs(d.int()).String()
+	sign := ""
+	if d.Sign() < 0 {
+		sign = "-"
+	}
+	if d.scale <= 0 {
+		return sign + digits
+	}
+	scale := int(d.scale)
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-scale] + "." + digits[len(digits)-scale:]
+}
+
+// Float64 returns nearest float64 value of d
+func (d Decimal) Float64() float64 {
+	f, _ := d.Rat().Float64()
+	return f
+}
+
+// Rat returns d as big.Rat
+func (d Decimal) Rat() *big.Rat {
+	return new(big.Rat).SetFrac(d.int(), pow10(int64(d.scale)))
+}
+
+// Sign returns -1, 0 or 1
+func (d Decimal) Sign() int {
+	return d.int().Sign()
+}
+
+// IsZero returns true when d is 0
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Cmp returns -1 when d < other, 0 when d == other and 1 when d > other
+func (d Decimal) Cmp(other Decimal) int {
+	a, b, _ := align(d, other)
+	return a.Cmp(b)
+}
+
+// Add returns d + other
+func (d Decimal) Add(other Decimal) Decimal {
+	a, b, scale := align(d, other)
+	return newDecimal(new(big.Int).Add(a, b), scale)
+}
+
+// Sub returns d - other
+func (d Decimal) Sub(other Decimal) Decimal {
+	a, b, scale := align(d, other)
+	return newDecimal(new(big.Int).Sub(a, b), scale)
+}
+
+// Mul returns d * other
+func (d Decimal) Mul(other Decimal) Decimal {
+	return newDecimal(new(big.Int).Mul(d.int(), other.int()), d.scale+other.scale)
+}
+
+// Neg returns -d
+func (d Decimal) Neg() Decimal {
+	return newDecimal(new(big.Int).Neg(d.int()), d.scale)
+}
+
+// Abs returns |d|
+func (d Decimal) Abs() Decimal {
+	return newDecimal(new(big.Int).Abs(d.int()), d.scale)
+}
+
+// SumDecimals returns sum of all values
+func SumDecimals(values ...Decimal) Decimal {
+	sum := Decimal{}
+	for _, v := range values {
+		sum = sum.Add(v)
+	}
+	return sum
+}
+
+// SumAmounts returns exact sum of Amount of all transactions
+func SumAmounts(transactions []Transaction) Decimal {
+	sum := Decimal{}
+	for _, tx := range transactions {
+		sum = sum.Add(tx.Amount)
+	}
+	return sum
+}
+
+// SumAmountsUSD returns exact sum of AmountUSD of all transactions
+func SumAmountsUSD(transactions []Transaction) Decimal {
+	sum := Decimal{}
+	for _, tx := range transactions {
+		sum = sum.Add(tx.AmountUSD)
+	}
+	return sum
+}
+
+// AmountFloat returns Amount as float64, precision may be lost
+func (t Transaction) AmountFloat() float64 {
+	return t.Amount.Float64()
+}
+
+// AmountUSDFloat returns AmountUSD as float64, precision may be lost
+func (t Transaction) AmountUSDFloat() float64 {
+	return t.AmountUSD.Float64()
+}
+
+// int returns unscaled value, empty text is treated as 0
+func (d Decimal) int() *big.Int {
+	i, ok := new(big.Int).SetString(d.unscaled, 10)
+	if !ok {
+		return new(big.Int)
+	}
+	return i
+}
+
+// align returns unscaled values of a and b with common scale
+func align(a, b Decimal) (*big.Int, *big.Int, int32) {
+	x, y := a.int(), b.int()
+	switch {
+	case a.scale < b.scale:
+		x = new(big.Int).Mul(x, pow10(int64(b.scale-a.scale)))
+		return x, y, b.scale
+	case a.scale > b.scale:
+		y = new(big.Int).Mul(y, pow10(int64(a.scale-b.scale)))
+		return x, y, a.scale
+	}
+	return x, y, a.scale
+}
+
+// pow10 returns 10^n
+func pow10(n int64) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(n), nil)
+}
@@ -0,0 +1,139 @@
+package whalealertapi_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	whalealertapi "github.com/devbay-io/whale_alert_api_client"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"0", "0"},
+		{"824064.6", "824064.6"},
+		{"-517499.72", "-517499.72"},
+		{"+5", "5"},
+		{"0.000001", "0.000001"},
+		{".5", "0.5"},
+		{"5.", "5"},
+		{"1.5e6", "1500000"},
+		{"1.5E-3", "0.0015"},
+		{"1e1000", "1" + strings.Repeat("0", 1000)},
+		{"1e-1000", "0." + strings.Repeat("0", 999) + "1"},
+		{"123456789012345678901234567890.123456789", "123456789012345678901234567890.123456789"},
+	}
+	for _, test := range tests {
+		d, err := whalealertapi.ParseDecimal(test.value)
+		if err != nil {
+			t.Errorf("%s: expected nil, got %s", test.value, err)
+			continue
+		}
+		if d.String() != test.expected {
+			t.Errorf("Expected %s, got %s", test.expected, d)
+		}
+	}
+	// Huge exponents and scales are rejected, so they can't hang arithmetic
+	invalid := []string{"", "-", ".", "abc", "1.2.3", "1e", "1ex", "0x10",
+		"1e1001", "1e-1001", "1e900000000", "1e-900000000", "0." + strings.Repeat("0", 1000) + "1"}
+	for _, value := range invalid {
+		if _, err := whalealertapi.ParseDecimal(value); err == nil {
+			t.Errorf("%.20q: expected error", value)
+		}
+	}
+	var tx whalealertapi.Transaction
+	if err := json.Unmarshal([]byte(`{"amount":1e-900000000}`), &tx); err == nil {
+		t.Errorf("Expected error for huge exponent")
+	}
+}
+
+func TestDecimalJSON(t *testing.T) {
+	data := []byte(`{"amount":2500000000.123456789012,"amount_usd":2500000001,"id":"1"}`)
+	var tx whalealertapi.Transaction
+	if err := json.Unmarshal(data, &tx); err != nil {
+		t.Fatalf("Expected nil, got %s", err)
+	}
+	if tx.Amount.String() != "2500000000.123456789012" {
+		t.Errorf("Expected %s, got %s", "2500000000.123456789012", tx.Amount)
+	}
+	if tx.AmountUSDFloat() != 2500000001 {
+		t.Errorf("Expected %f, got %f", 2500000001.0, tx.AmountUSDFloat())
+	}
+	out, err := json.Marshal(tx)
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err)
+	}
+	var again whalealertapi.Transaction
+	json.Unmarshal(out, &again)
+	if again.Amount.Cmp(tx.Amount) != 0 {
+		t.Errorf("Expected %s after round trip, got %s", tx.Amount, again.Amount)
+	}
+
+	// Transactions decoded from the same JSON are equal and can be map keys
+	var same whalealertapi.Transaction
+	json.Unmarshal(data, &same)
+	if same != tx {
+		t.Errorf("Expected %+v, got %+v", tx, same)
+	}
+	if _, ok := map[whalealertapi.Transaction]bool{tx: true}[same]; !ok {
+		t.Errorf("Expected transaction to be found in map")
+	}
+	if whalealertapi.MustParseDecimal("0") != (whalealertapi.Decimal{}) || whalealertapi.MustParseDecimal("1.50") == whalealertapi.MustParseDecimal("1.5") {
+		t.Errorf("Expected == to compare value and scale")
+	}
+
+	var d whalealertapi.Decimal
+	if err := json.Unmarshal([]byte(`"12.5"`), &d); err != nil || d.String() != "12.5" {
+		t.Errorf("Expected 12.5 from string, got (%s, %v)", d, err)
+	}
+	if err := json.Unmarshal([]byte(`true`), &d); err == nil {
+		t.Errorf("Expected error for boolean")
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	a := whalealertapi.MustParseDecimal("0.1")
+	b := whalealertapi.MustParseDecimal("0.2")
+	if sum := a.Add(b); sum.Cmp(whalealertapi.MustParseDecimal("0.3")) != 0 {
+		t.Errorf("Expected 0.3, got %s", sum)
+	}
+	if diff := a.Sub(b); diff.String() != "-0.1" || diff.Sign() != -1 {
+		t.Errorf("Expected -0.1, got %s", diff)
+	}
+	if product := a.Mul(whalealertapi.NewDecimalFromInt(30)); product.Cmp(whalealertapi.NewDecimalFromInt(3)) != 0 {
+		t.Errorf("Expected 3, got %s", product)
+	}
+	if a.Neg().Abs().Cmp(a) != 0 {
+		t.Errorf("Expected %s, got %s", a, a.Neg().Abs())
+	}
+	if whalealertapi.MustParseDecimal("1.50").Cmp(whalealertapi.MustParseDecimal("1.5")) != 0 {
+		t.Errorf("Expected equal values with different scale")
+	}
+	var zero whalealertapi.Decimal
+	if !zero.IsZero() || zero.String() != "0" || zero.Add(a).Cmp(a) != 0 {
+		t.Errorf("Expected zero value to be usable as 0")
+	}
+	if f := whalealertapi.NewDecimalFromFloat(824064.6); f.String() != "824064.6" {
+		t.Errorf("Expected 824064.6, got %s", f)
+	}
+
+	transactions := []whalealertapi.Transaction{}
+	for i := 0; i < 1000; i++ {
+		transactions = append(transactions, whalealertapi.Transaction{
+			Amount:    whalealertapi.MustParseDecimal("0.1"),
+			AmountUSD: whalealertapi.MustParseDecimal("1000000.01"),
+		})
+	}
+	if sum := whalealertapi.SumAmounts(transactions); sum.Cmp(whalealertapi.NewDecimalFromInt(100)) != 0 {
+		t.Errorf("Expected 100, got %s", sum)
+	}
+	if sum := whalealertapi.SumAmountsUSD(transactions); sum.String() != "1000000010.00" {
+		t.Errorf("Expected 1000000010.00, got %s", sum)
+	}
+	if sum := whalealertapi.SumDecimals(a, b, a); sum.String() != "0.4" {
+		t.Errorf("Expected 0.4, got %s", sum)
+	}
+}
@@ -0,0 +1,36 @@
+package whalealertapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Do calls any endpoint of the API and decodes successful response to T
+// It allows using endpoints and parameters the client doesn't wrap yet,
+// request goes through the same url building, authentication, retries, rate limiting and error handling as other methods
+// HEAD requests and 204 responses have no body, so result is nil, use DoWithMeta to read their metadata
+//
+//	res, err := Do[StatusResponse](ctx, api, http.MethodGet, "/status", nil)
+func Do[T any](ctx context.Context, api *WhaleAlertAPI, method, endpoint string, params []APIArgument) (*T, error) {
+	result, _, err := DoWithMeta[T](ctx, api, method, endpoint, params)
+	return result, err
+}
+
+// DoWithMeta is Do which returns response metadata too
+func DoWithMeta[T any](ctx context.Context, api *WhaleAlertAPI, method, endpoint string, params []APIArgument) (*T, *ResponseMeta, error) {
+	if method == "" {
+		return nil, nil, fmt.Errorf("method is required")
+	}
+	return doWithMeta[T](ctx, *api, strings.ToUpper(method), endpoint, params)
+}
+
+// DoRaw calls any endpoint of the API like Do and returns response body as raw JSON
+func DoRaw(ctx context.Context, api *WhaleAlertAPI, method, endpoint string, params []APIArgument) (json.RawMessage, error) {
+	result, err := Do[json.RawMessage](ctx, api, method, endpoint, params)
+	if err != nil || result == nil {
+		return nil, err
+	}
+	return *result, nil
+}
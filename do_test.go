@@ -0,0 +1,95 @@
+package whalealertapi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	whalealertapi "github.com/devbay-io/whale_alert_api_client"
+)
+
+func TestDo(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.Header.Get("X-WA-API-KEY") != "CORRECT" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"result":"error","message":"invalid api_key"}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/no_content":
+			w.WriteHeader(http.StatusNoContent)
+		case "/new_endpoint":
+			w.Write([]byte(`{"result":"success","method":"` + r.Method + `","value":"` + r.URL.Query().Get("param") + `"}`))
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"result":"error","message":"unavailable"}`))
+		}
+	}))
+	defer server.Close()
+
+	policy := whalealertapi.DefaultRetryPolicy()
+	policy.BaseBackoff = 0
+	api := whalealertapi.New(whalealertapi.WithURL(server.URL), whalealertapi.WithAccessKey("CORRECT"), whalealertapi.WithRetryPolicy(policy))
+	params := []whalealertapi.APIArgument{{Key: "param", Value: "a b"}}
+
+	type newResponse struct {
+		Result string `json:"result"`
+		Method string `json:"method"`
+		Value  string `json:"value"`
+	}
+	res, err := whalealertapi.Do[newResponse](context.Background(), api, "get", "/new_endpoint", params)
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err)
+	}
+	if res.Method != http.MethodGet || res.Value != "a b" {
+		t.Errorf("Unexpected response %+v", res)
+	}
+
+	raw, err := whalealertapi.DoRaw(context.Background(), api, http.MethodGet, "/new_endpoint", params)
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err)
+	}
+	if string(raw) != `{"result":"success","method":"GET","value":"a b"}` {
+		t.Errorf("Unexpected raw response %s", raw)
+	}
+
+	_, err = whalealertapi.DoRaw(context.Background(), api.With(whalealertapi.WithAccessKey("WRONG")), http.MethodGet, "/new_endpoint", nil)
+	if !errors.Is(err, whalealertapi.ErrUnauthorized) {
+		t.Errorf("Expected %s, got %v", whalealertapi.ErrUnauthorized, err)
+	}
+
+	// HEAD and 204 responses return only metadata
+	res, meta, err := whalealertapi.DoWithMeta[newResponse](context.Background(), api, http.MethodHead, "/new_endpoint", nil)
+	if err != nil || res != nil || meta.StatusCode != http.StatusOK {
+		t.Errorf("Expected nil result with metadata, got %v, %+v, %v", res, meta, err)
+	}
+	raw, err = whalealertapi.DoRaw(context.Background(), api, http.MethodHead, "/new_endpoint", nil)
+	if err != nil || raw != nil {
+		t.Errorf("Expected nil, got %s, %v", raw, err)
+	}
+	res, meta, err = whalealertapi.DoWithMeta[newResponse](context.Background(), api, http.MethodPost, "/no_content", nil)
+	if err != nil || res != nil || meta.StatusCode != http.StatusNoContent {
+		t.Errorf("Expected nil result with metadata, got %v, %+v, %v", res, meta, err)
+	}
+
+	// Only GET requests are repeated
+	calls.Store(0)
+	_, err = whalealertapi.DoRaw(context.Background(), api, http.MethodGet, "/unavailable", nil)
+	if !errors.Is(err, whalealertapi.ErrServerError) || calls.Load() != int32(policy.MaxAttempts) {
+		t.Errorf("Expected %d attempts and %s, got %d and %v", policy.MaxAttempts, whalealertapi.ErrServerError, calls.Load(), err)
+	}
+	calls.Store(0)
+	_, err = whalealertapi.DoRaw(context.Background(), api, http.MethodPost, "/unavailable", nil)
+	if !errors.Is(err, whalealertapi.ErrServerError) || calls.Load() != 1 {
+		t.Errorf("Expected %d attempt and %s, got %d and %v", 1, whalealertapi.ErrServerError, calls.Load(), err)
+	}
+
+	if _, err := whalealertapi.DoRaw(context.Background(), api, "", "/new_endpoint", nil); err == nil {
+		t.Errorf("Expected error for missing method")
+	}
+}
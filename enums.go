@@ -0,0 +1,186 @@
+package whalealertapi
+
+import "strings"
+
+// BlockchainName is name of blockchain as used by the API
+// Values not known to this package are kept as they are
+type BlockchainName string
+
+const (
+	BlockchainBinanceChain BlockchainName = "binancechain"
+	BlockchainBitcoin      BlockchainName = "bitcoin"
+	BlockchainCosmos       BlockchainName = "cosmos"
+	BlockchainEOS          BlockchainName = "eos"
+	BlockchainEthereum     BlockchainName = "ethereum"
+	BlockchainHive         BlockchainName = "hive"
+	BlockchainIcon         BlockchainName = "icon"
+	BlockchainLiquid       BlockchainName = "liquid"
+	BlockchainNeo          BlockchainName = "neo"
+	BlockchainRipple       BlockchainName = "ripple"
+	BlockchainSteem        BlockchainName = "steem"
+	BlockchainStellar      BlockchainName = "stellar"
+	BlockchainTezos        BlockchainName = "tezos"
+	BlockchainTron         BlockchainName = "tron"
+	BlockchainUnknown      BlockchainName = "unknown"
+)
+
+var knownBlockchains = []BlockchainName{
+	BlockchainBinanceChain,
+	BlockchainBitcoin,
+	BlockchainCosmos,
+	BlockchainEOS,
+	BlockchainEthereum,
+	BlockchainHive,
+	BlockchainIcon,
+	BlockchainLiquid,
+	BlockchainNeo,
+	BlockchainRipple,
+	BlockchainSteem,
+	BlockchainStellar,
+	BlockchainTezos,
+	BlockchainTron,
+	BlockchainUnknown,
+}
+
+// TransactionType is type of transaction
+type TransactionType string
+
+const (
+	TransactionTransfer TransactionType = "transfer"
+	TransactionMint     TransactionType = "mint"
+	TransactionBurn     TransactionType = "burn"
+	TransactionLock     TransactionType = "lock"
+	TransactionUnlock   TransactionType = "unlock"
+)
+
+var knownTransactionTypes = []TransactionType{
+	TransactionTransfer,
+	TransactionMint,
+	TransactionBurn,
+	TransactionLock,
+	TransactionUnlock,
+}
+
+// OwnerType is type of wallet address owner
+type OwnerType string
+
+const (
+	OwnerExchange OwnerType = "exchange"
+	OwnerOther    OwnerType = "other"
+	OwnerUnknown  OwnerType = "unknown"
+)
+
+var knownOwnerTypes = []OwnerType{
+	OwnerExchange,
+	OwnerOther,
+	OwnerUnknown,
+}
+
+// BlockchainStatus is status of blockchain reported by /status endpoint
+type BlockchainStatus string
+
+const (
+	BlockchainConnected    BlockchainStatus = "connected"
+	BlockchainDisconnected BlockchainStatus = "disconnected"
+)
+
+var knownBlockchainStatuses = []BlockchainStatus{
+	BlockchainConnected,
+	BlockchainDisconnected,
+}
+
+func (b BlockchainName) String() string {
+	return string(b)
+}
+
+// IsKnown returns true when b is one of Blockchain* constants
+func (b BlockchainName) IsKnown() bool {
+	return isKnown(b, knownBlockchains)
+}
+
+func (b BlockchainName) MarshalText() ([]byte, error) {
+	return []byte(b), nil
+}
+
+// UnmarshalText normalizes case of known values, unknown values are kept as they are
+func (b *BlockchainName) UnmarshalText(text []byte) error {
+	*b = canonical(string(text), knownBlockchains)
+	return nil
+}
+
+func (t TransactionType) String() string {
+	return string(t)
+}
+
+// IsKnown returns true when t is one of Transaction* constants
+func (t TransactionType) IsKnown() bool {
+	return isKnown(t, knownTransactionTypes)
+}
+
+func (t TransactionType) MarshalText() ([]byte, error) {
+	return []byte(t), nil
+}
+
+// UnmarshalText normalizes case of known values, unknown values are kept as they are
+func (t *TransactionType) UnmarshalText(text []byte) error {
+	*t = canonical(string(text), knownTransactionTypes)
+	return nil
+}
+
+func (o OwnerType) String() string {
+	return string(o)
+}
+
+// IsKnown returns true when o is one of Owner* constants
+func (o OwnerType) IsKnown() bool {
+	return isKnown(o, knownOwnerTypes)
+}
+
+func (o OwnerType) MarshalText() ([]byte, error) {
+	return []byte(o), nil
+}
+
+// UnmarshalText normalizes case of known values, unknown values are kept as they are
+func (o *OwnerType) UnmarshalText(text []byte) error {
+	*o = canonical(string(text), knownOwnerTypes)
+	return nil
+}
+
+func (s BlockchainStatus) String() string {
+	return string(s)
+}
+
+// IsKnown returns true when s is one of BlockchainConnected, BlockchainDisconnected
+func (s BlockchainStatus) IsKnown() bool {
+	return isKnown(s, knownBlockchainStatuses)
+}
+
+func (s BlockchainStatus) MarshalText() ([]byte, error) {
+	return []byte(s), nil
+}
+
+// UnmarshalText normalizes case of known values, unknown values are kept as they are
+func (s *BlockchainStatus) UnmarshalText(text []byte) error {
+	*s = canonical(string(text), knownBlockchainStatuses)
+	return nil
+}
+
+// isKnown returns true when value is one of known values
+func isKnown[T ~string](value T, known []T) bool {
+	for _, k := range known {
+		if value == k {
+			return true
+		}
+	}
+	return false
+}
+
+// canonical returns known value equal to value ignoring case, or value itself
+func canonical[T ~string](value string, known []T) T {
+	for _, k := range known {
+		if strings.EqualFold(string(k), value) {
+			return k
+		}
+	}
+	return T(value)
+}
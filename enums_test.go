@@ -0,0 +1,74 @@
+package whalealertapi_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	whalealertapi "github.com/devbay-io/whale_alert_api_client"
+)
+
+func TestEnumsJSON(t *testing.T) {
+	data := []byte(`{"blockchain":"Ethereum","transaction_type":"Transfer","from":{"owner_type":"EXCHANGE"},"to":{"owner_type":"hot wallet"},"id":"1"}`)
+	var tx whalealertapi.Transaction
+	if err := json.Unmarshal(data, &tx); err != nil {
+		t.Fatalf("Expected nil, got %s", err)
+	}
+	if tx.Blockchain != whalealertapi.BlockchainEthereum {
+		t.Errorf("Expected %s, got %s", whalealertapi.BlockchainEthereum, tx.Blockchain)
+	}
+	if tx.TransactionType != whalealertapi.TransactionTransfer {
+		t.Errorf("Expected %s, got %s", whalealertapi.TransactionTransfer, tx.TransactionType)
+	}
+	if tx.From.OwnerType != whalealertapi.OwnerExchange {
+		t.Errorf("Expected %s, got %s", whalealertapi.OwnerExchange, tx.From.OwnerType)
+	}
+	if tx.To.OwnerType != "hot wallet" || tx.To.OwnerType.IsKnown() {
+		t.Errorf("Expected unknown %s, got %s", "hot wallet", tx.To.OwnerType)
+	}
+
+	out, err := json.Marshal(tx)
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err)
+	}
+	var again whalealertapi.Transaction
+	if err := json.Unmarshal(out, &again); err != nil {
+		t.Fatalf("Expected nil, got %s", err)
+	}
+	if again.Blockchain != tx.Blockchain || again.TransactionType != tx.TransactionType || again.To.OwnerType != tx.To.OwnerType {
+		t.Errorf("Expected %+v after round trip, got %+v", tx, again)
+	}
+
+	var blockchain whalealertapi.Blockchain
+	if err := json.Unmarshal([]byte(`{"name":"solana","symbols":["sol"],"status":"delayed"}`), &blockchain); err != nil {
+		t.Fatalf("Expected nil, got %s", err)
+	}
+	if blockchain.Name != "solana" || blockchain.Name.IsKnown() {
+		t.Errorf("Expected unknown %s, got %s", "solana", blockchain.Name)
+	}
+	if blockchain.Status != "delayed" || blockchain.Status.IsKnown() {
+		t.Errorf("Expected unknown %s, got %s", "delayed", blockchain.Status)
+	}
+}
+
+func TestEnumsIsKnown(t *testing.T) {
+	if !whalealertapi.BlockchainBitcoin.IsKnown() || whalealertapi.BlockchainName("etherium").IsKnown() {
+		t.Errorf("Unexpected IsKnown for blockchains")
+	}
+	for _, tt := range []whalealertapi.TransactionType{
+		whalealertapi.TransactionTransfer,
+		whalealertapi.TransactionMint,
+		whalealertapi.TransactionBurn,
+		whalealertapi.TransactionLock,
+		whalealertapi.TransactionUnlock,
+	} {
+		if !tt.IsKnown() {
+			t.Errorf("Expected %s to be known", tt)
+		}
+	}
+	if whalealertapi.TransactionType("Transfer").IsKnown() {
+		t.Errorf("Expected IsKnown to be case sensitive")
+	}
+	if !whalealertapi.OwnerUnknown.IsKnown() || !whalealertapi.BlockchainDisconnected.IsKnown() {
+		t.Errorf("Unexpected IsKnown for owner types and statuses")
+	}
+}
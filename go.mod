@@ -1,3 +1,3 @@
 module github.com/devbay-io/whale_alert_api_client
 
-go 1.19
+go 1.23
@@ -0,0 +1,116 @@
+package whalealertapi
+
+import (
+	"context"
+	"iter"
+)
+
+// DefaultTransactionsLimit is number of transactions returned by /transactions when limit is not set
+const DefaultTransactionsLimit = 100
+
+// TransactionsIterator fetches pages from /transactions lazily, following the cursor
+// Transactions are deduplicated by ID
+//
+//	it := api.IterateTransactions(ctx, start, TransactionsRequest{})
+//	for it.Next() {
+//		tx := it.Transaction()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type TransactionsIterator struct {
+	api     WhaleAlertAPI
+	ctx     context.Context
+	request TransactionsRequest
+	seen    map[string]struct{}
+	page    []Transaction
+	current Transaction
+	done    bool
+	err     error
+}
+
+// IterateTransactions returns iterator over all transactions matching args, starting from start
+func (api WhaleAlertAPI) IterateTransactions(ctx context.Context, start uint, args TransactionsRequest) *TransactionsIterator {
+	args.Start = start
+	return &TransactionsIterator{
+		api:     api,
+		ctx:     ctx,
+		request: args,
+		seen:    map[string]struct{}{},
+	}
+}
+
+// AllTransactions returns range-over-func form of IterateTransactions
+// Iteration stops after first error
+func (api WhaleAlertAPI) AllTransactions(ctx context.Context, start uint, args TransactionsRequest) iter.Seq2[Transaction, error] {
+	return func(yield func(Transaction, error) bool) {
+		it := api.IterateTransactions(ctx, start, args)
+		for it.Next() {
+			if !yield(it.Transaction(), nil) {
+				return
+			}
+		}
+		if err := it.Err(); err != nil {
+			yield(Transaction{}, err)
+		}
+	}
+}
+
+// Next advances to next transaction, fetching next page when needed
+// It returns false when there are no more transactions or an error occurred
+// After an error Next can be called again, it retries fetching the same page
+func (it *TransactionsIterator) Next() bool {
+	it.err = nil
+	for {
+		for len(it.page) > 0 {
+			tx := it.page[0]
+			it.page = it.page[1:]
+			if _, ok := it.seen[tx.ID]; ok {
+				continue
+			}
+			it.seen[tx.ID] = struct{}{}
+			it.current = tx
+			return true
+		}
+		if it.done {
+			return false
+		}
+		if err := it.fetch(); err != nil {
+			it.err = err
+			return false
+		}
+	}
+}
+
+// fetch loads next page and advances the cursor, on error position is not changed
+func (it *TransactionsIterator) fetch() error {
+	res, err := it.api.TransactionsContext(it.ctx, it.request.Start, it.request)
+	if err != nil {
+		return err
+	}
+	limit := it.request.Limit
+	if limit == 0 {
+		limit = DefaultTransactionsLimit
+	}
+	if uint(len(res.Transactions)) < limit || res.Cursor == "" || res.Cursor == it.request.Cursor {
+		it.done = true
+	}
+	it.page = res.Transactions
+	it.request.Cursor = res.Cursor
+	return nil
+}
+
+// Transaction returns transaction loaded by last Next call
+func (it *TransactionsIterator) Transaction() Transaction {
+	return it.current
+}
+
+// Err returns error which stopped the iteration
+func (it *TransactionsIterator) Err() error {
+	return it.err
+}
+
+// Cursor returns cursor of the next page to fetch
+func (it *TransactionsIterator) Cursor() string {
+	return it.request.Cursor
+}
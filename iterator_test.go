@@ -0,0 +1,135 @@
+package whalealertapi_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	whalealertapi "github.com/devbay-io/whale_alert_api_client"
+)
+
+// transactionsPage returns /transactions response with transactions of given ids
+func transactionsPage(cursor string, ids ...int) string {
+	txs := []string{}
+	for _, id := range ids {
+		txs = append(txs, fmt.Sprintf(`{"blockchain":"ethereum","symbol":"eth","id":"%d","transaction_type":"transfer","hash":"%x","timestamp":%d,"amount":1,"amount_usd":1800,"transaction_count":1}`, id, id, 1679774500+id))
+	}
+	return fmt.Sprintf(`{"result":"success","cursor":"%s","count":%d,"transactions":[%s]}`, cursor, len(ids), strings.Join(txs, ","))
+}
+
+func TestTransactionsIterator(t *testing.T) {
+	failed := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("limit") != "2" || r.URL.Query().Get("start") != startTimeWithRecords {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"result":"error","message":"invalid request"}`))
+			return
+		}
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			w.Write([]byte(transactionsPage("p1", 1, 2)))
+		case "p1":
+			w.Write([]byte(transactionsPage("p2", 2, 3)))
+		case "p2":
+			if !failed {
+				failed = true
+				w.WriteHeader(http.StatusServiceUnavailable)
+				w.Write([]byte(`{"result":"error","message":"unavailable"}`))
+				return
+			}
+			w.Write([]byte(transactionsPage("p3", 4, 5)))
+		default:
+			w.Write([]byte(`{"result":"success","cursor":"p3","count":0}`))
+		}
+	}))
+	defer server.Close()
+
+	api := whalealertapi.New().WithCustomURL(server.URL).WithAccessKey("CORRECT")
+	it := api.IterateTransactions(context.Background(), 1679774508, whalealertapi.TransactionsRequest{Limit: 2})
+	ids := []string{}
+	for it.Next() {
+		ids = append(ids, it.Transaction().ID)
+	}
+	if !errors.Is(it.Err(), whalealertapi.ErrServerError) {
+		t.Fatalf("Expected %s, got %v", whalealertapi.ErrServerError, it.Err())
+	}
+	if it.Cursor() != "p2" {
+		t.Errorf("Expected cursor %s, got %s", "p2", it.Cursor())
+	}
+	for it.Next() {
+		ids = append(ids, it.Transaction().ID)
+	}
+	if it.Err() != nil {
+		t.Errorf("Expected nil, got %s", it.Err())
+	}
+	if strings.Join(ids, ",") != "1,2,3,4,5" {
+		t.Errorf("Expected %s, got %s", "1,2,3,4,5", strings.Join(ids, ","))
+	}
+	if it.Next() {
+		t.Errorf("Expected exhausted iterator")
+	}
+}
+
+func TestTransactionsIteratorStopsOnShortPage(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(transactionsPage(fmt.Sprintf("c%d", calls), calls)))
+	}))
+	defer server.Close()
+
+	api := whalealertapi.New().WithCustomURL(server.URL).WithAccessKey("CORRECT")
+	count := 0
+	for tx, err := range api.AllTransactions(context.Background(), 1679774508, whalealertapi.TransactionsRequest{}) {
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err)
+		}
+		if tx.ID != "1" {
+			t.Errorf("Expected %s, got %s", "1", tx.ID)
+		}
+		count++
+	}
+	if count != 1 || calls != 1 {
+		t.Errorf("Expected single transaction from single call, got %d transactions from %d calls", count, calls)
+	}
+}
+
+func TestTransactionsIteratorStopsOnSameCursor(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(transactionsPage("same", calls)))
+	}))
+	defer server.Close()
+
+	api := whalealertapi.New().WithCustomURL(server.URL).WithAccessKey("CORRECT")
+	count := 0
+	for _, err := range api.AllTransactions(context.Background(), 1679774508, whalealertapi.TransactionsRequest{Limit: 1}) {
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err)
+		}
+		count++
+	}
+	if count != 2 || calls != 2 {
+		t.Errorf("Expected 2 transactions from 2 calls, got %d transactions from %d calls", count, calls)
+	}
+}
+
+func TestAllTransactionsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"result":"error","message":"invalid api_key"}`))
+	}))
+	defer server.Close()
+
+	api := whalealertapi.New().WithCustomURL(server.URL).WithAccessKey("WRONG")
+	for _, err := range api.AllTransactions(context.Background(), 1679774508, whalealertapi.TransactionsRequest{}) {
+		if !errors.Is(err, whalealertapi.ErrUnauthorized) {
+			t.Errorf("Expected %s, got %v", whalealertapi.ErrUnauthorized, err)
+		}
+	}
+}
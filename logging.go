@@ -0,0 +1,139 @@
+package whalealertapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// LogLevels are levels of messages logged by the client
+type LogLevels struct {
+	// Request is level of message logged before every attempt
+	Request slog.Level
+	// Response is level of message logged after successful attempt
+	Response slog.Level
+	// Error is level of message logged after failed attempt
+	Error slog.Level
+	// Retry is level of message logged when failed attempt will be repeated
+	Retry slog.Level
+}
+
+// DefaultLogLevels returns levels logging successful responses at info, failures at warn, requests and retries at debug
+func DefaultLogLevels() LogLevels {
+	return LogLevels{
+		Request:  slog.LevelDebug,
+		Response: slog.LevelInfo,
+		Error:    slog.LevelWarn,
+		Retry:    slog.LevelDebug,
+	}
+}
+
+// WithLogLevels sets levels of messages logged by the client, logger is set by WithLogger
+func WithLogLevels(levels LogLevels) Option {
+	return func(api *WhaleAlertAPI) {
+		api.logLevels = levels
+	}
+}
+
+// logRequest logs that attempt is going to be sent, access key is redacted from query
+func (api WhaleAlertAPI) logRequest(ctx context.Context, method, endpoint, requestURL string, attempt int) {
+	if api.logger == nil || !api.logger.Enabled(ctx, api.logLevels.Request) {
+		return
+	}
+	api.logger.Log(ctx, api.logLevels.Request, "sending request",
+		"method", method,
+		"endpoint", endpoint,
+		"query", redactedQuery(requestURL, api.key),
+		"attempt", attempt,
+	)
+}
+
+// logResponse logs finished attempt, err is error of reading or decoding the response
+func (api WhaleAlertAPI) logResponse(ctx context.Context, endpoint string, attempt int, latency time.Duration, status int, bytes int64, err error) {
+	if err != nil || status >= 400 {
+		api.logFailure(ctx, endpoint, attempt, latency, status, bytes, err)
+		return
+	}
+	if api.logger == nil {
+		return
+	}
+	api.logger.Log(ctx, api.logLevels.Response, "request finished",
+		"endpoint", endpoint,
+		"status", status,
+		"latency", latency,
+		"attempt", attempt,
+		"bytes", bytes,
+	)
+}
+
+// logFailure logs failed attempt, message of ErrorResponse is logged when API returned one
+func (api WhaleAlertAPI) logFailure(ctx context.Context, endpoint string, attempt int, latency time.Duration, status int, bytes int64, err error) {
+	if api.logger == nil {
+		return
+	}
+	attrs := []any{
+		"endpoint", endpoint,
+		"status", status,
+		"latency", latency,
+		"attempt", attempt,
+		"bytes", bytes,
+	}
+	var errResponse *ErrorResponse
+	switch {
+	case errors.As(err, &errResponse):
+		attrs = append(attrs, "message", errResponse.Message)
+	case err != nil:
+		attrs = append(attrs, "error", redactKey(err.Error(), api.key))
+	}
+	api.logger.Log(ctx, api.logLevels.Error, "request failed", attrs...)
+}
+
+// logRetry logs that failed attempt will be repeated after delay
+func (api WhaleAlertAPI) logRetry(ctx context.Context, endpoint string, attempt int, delay time.Duration, reason string, value any) {
+	if api.logger == nil {
+		return
+	}
+	api.logger.Log(ctx, api.logLevels.Retry, "retrying request",
+		"endpoint", endpoint,
+		"attempt", attempt,
+		"delay", delay,
+		reason, value,
+	)
+}
+
+// redactedQuery returns query of requestURL with access key redacted
+func redactedQuery(requestURL, key string) string {
+	u, err := url.Parse(requestURL)
+	if err != nil {
+		return ""
+	}
+	redacted := redactURL(u, key)
+	if i := strings.IndexByte(redacted, '?'); i >= 0 {
+		return redacted[i+1:]
+	}
+	return ""
+}
+
+// redactKey replaces access key in s
+func redactKey(s, key string) string {
+	if key == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, key, "REDACTED")
+}
+
+// countingReader counts bytes read from r
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
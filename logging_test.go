@@ -0,0 +1,70 @@
+package whalealertapi
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLogging(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/bad_request" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"result":"error","message":"invalid value for start"}`))
+			return
+		}
+		w.Write([]byte(`{"response": "OK!"}`))
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	api := New(
+		WithURL(server.URL),
+		WithAccessKey("TOPSECRET"),
+		WithLogger(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))),
+	)
+	args := []APIArgument{{Key: "api_key", Value: "TOPSECRET"}, {Key: "start", Value: "1"}}
+	if _, err := get[response](context.Background(), *api, "/ok", args); err != nil {
+		t.Fatalf("Expected nil, got %s", err)
+	}
+	out := logs.String()
+	all := out
+	for _, want := range []string{
+		"level=DEBUG msg=\"sending request\" method=GET endpoint=/ok query=\"api_key=REDACTED&start=1\" attempt=1",
+		"level=INFO msg=\"request finished\" endpoint=/ok status=200",
+		"bytes=19",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %s in logs, got %s", want, out)
+		}
+	}
+
+	logs.Reset()
+	if _, err := get[response](context.Background(), *api, "/bad_request", nil); err == nil {
+		t.Fatalf("Expected error")
+	}
+	out = logs.String()
+	all += out
+	if !strings.Contains(out, "level=WARN msg=\"request failed\" endpoint=/bad_request status=400") ||
+		!strings.Contains(out, "message=\"invalid value for start\"") {
+		t.Errorf("Expected failure to be logged, got %s", out)
+	}
+
+	// Levels are configurable
+	logs.Reset()
+	quiet := api.With(WithLogLevels(LogLevels{Request: slog.LevelDebug - 1, Response: slog.LevelDebug - 1, Error: slog.LevelError}))
+	get[response](context.Background(), *quiet, "/ok", nil)
+	get[response](context.Background(), *quiet, "/bad_request", nil)
+	out = logs.String()
+	all += out
+	if strings.Contains(out, "request finished") || !strings.Contains(out, "level=ERROR msg=\"request failed\"") {
+		t.Errorf("Expected only failure to be logged, got %s", out)
+	}
+	if strings.Contains(all, "TOPSECRET") {
+		t.Errorf("Expected access key to be redacted, got %s", all)
+	}
+}
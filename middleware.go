@@ -0,0 +1,158 @@
+package whalealertapi
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sync"
+)
+
+// Doer sends single http request, http.Client.Do is a Doer
+type Doer func(*http.Request) (*http.Response, error)
+
+// Middleware wraps Doer, it can modify request before calling next and response after it
+type Middleware func(next Doer) Doer
+
+// WithMiddleware adds middlewares wrapping every attempt of every request
+// First middleware is the outermost one, options can be used many times
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(api *WhaleAlertAPI) {
+		api.middlewares = append(append([]Middleware{}, api.middlewares...), middlewares...)
+	}
+}
+
+// WithTransport sets transport used by http client, for example proxy or tracing transport
+// Http client set with WithHTTPClient is not modified, a copy is used instead
+func WithTransport(transport http.RoundTripper) Option {
+	return func(api *WhaleAlertAPI) {
+		api.transport = transport
+	}
+}
+
+// doer returns http client Do wrapped with middlewares
+func (api WhaleAlertAPI) doer() Doer {
+	do := api.client.Do
+	for i := len(api.middlewares) - 1; i >= 0; i-- {
+		do = api.middlewares[i](do)
+	}
+	return do
+}
+
+// UserAgentMiddleware sets User-Agent header
+func UserAgentMiddleware(userAgent string) Middleware {
+	return func(next Doer) Doer {
+		return func(req *http.Request) (*http.Response, error) {
+			req.Header.Set("User-Agent", userAgent)
+			return next(req)
+		}
+	}
+}
+
+type requestIDKey struct{}
+
+// ContextWithRequestID returns context which makes RequestIDMiddleware use given ID
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+type callIDKey struct{}
+
+// callID is random request ID generated once and shared by all attempts of a call
+type callID struct {
+	once sync.Once
+	id   string
+}
+
+func (c *callID) get() string {
+	c.once.Do(func() { c.id = randomID() })
+	return c.id
+}
+
+// withCallID returns context in which RequestIDMiddleware uses the same random ID for every attempt
+func withCallID(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(callIDKey{}).(*callID); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, callIDKey{}, &callID{})
+}
+
+// RequestIDMiddleware sets header (X-Request-ID when empty) to ID from ContextWithRequestID or to random ID
+// Random ID is generated once per call, so all attempts of the request share the same ID
+// Header which is already set is not changed
+func RequestIDMiddleware(header string) Middleware {
+	if header == "" {
+		header = "X-Request-ID"
+	}
+	return func(next Doer) Doer {
+		return func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get(header) == "" {
+				id, ok := req.Context().Value(requestIDKey{}).(string)
+				if !ok || id == "" {
+					if call, ok := req.Context().Value(callIDKey{}).(*callID); ok {
+						id = call.get()
+					} else {
+						id = randomID()
+					}
+				}
+				req.Header.Set(header, id)
+			}
+			return next(req)
+		}
+	}
+}
+
+// DumpMiddleware writes every request and response to w, access key is redacted
+// Writes are serialized, so w does not have to be safe for concurrent use
+func DumpMiddleware(w io.Writer) Middleware {
+	var mu sync.Mutex
+	return func(next Doer) Doer {
+		return func(req *http.Request) (*http.Response, error) {
+			key := req.Header.Get("X-WA-API-KEY")
+			redacted := req.Clone(req.Context())
+			if key != "" {
+				redacted.Header.Set("X-WA-API-KEY", "REDACTED")
+			}
+			if u, err := url.Parse(redactURL(req.URL, key)); err == nil {
+				redacted.URL = u
+			}
+			dump, err := httputil.DumpRequestOut(redacted, false)
+			if err != nil {
+				return nil, err
+			}
+			mu.Lock()
+			w.Write(dump)
+			mu.Unlock()
+
+			response, err := next(req)
+			if err != nil {
+				mu.Lock()
+				fmt.Fprintf(w, "error: %s\n\n", err)
+				mu.Unlock()
+				return response, err
+			}
+			dump, err = httputil.DumpResponse(response, true)
+			if err != nil {
+				// Body is partially read and can't be returned, close it to release the connection
+				response.Body.Close()
+				return nil, err
+			}
+			mu.Lock()
+			w.Write(dump)
+			w.Write([]byte("\n\n"))
+			mu.Unlock()
+			return response, nil
+		}
+	}
+}
+
+// randomID returns random 16 bytes as hex
+func randomID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,170 @@
+package whalealertapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestMiddlewareChain(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"response": "` + r.Header.Get("X-Order") + `"}`))
+	}))
+	defer server.Close()
+
+	order := func(name string) Middleware {
+		return func(next Doer) Doer {
+			return func(req *http.Request) (*http.Response, error) {
+				req.Header.Set("X-Order", strings.TrimPrefix(req.Header.Get("X-Order")+","+name, ","))
+				return next(req)
+			}
+		}
+	}
+	api := New(WithURL(server.URL), WithAccessKey("KEY"), WithMiddleware(order("a"), order("b")), WithMiddleware(order("c")))
+	res, err := get[response](context.Background(), *api, "/ok", nil)
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err)
+	}
+	if res.Response != "a,b,c" {
+		t.Errorf("Expected %s, got %s", "a,b,c", res.Response)
+	}
+
+	// Middleware can short-circuit the request
+	injected := errors.New("injected fault")
+	faulty := api.With(WithMiddleware(func(next Doer) Doer {
+		return func(req *http.Request) (*http.Response, error) {
+			return nil, injected
+		}
+	}))
+	if _, err := get[response](context.Background(), *faulty, "/ok", nil); !errors.Is(err, injected) {
+		t.Errorf("Expected %s, got %v", injected, err)
+	}
+	if len(api.middlewares) != 3 {
+		t.Errorf("Expected original client to keep %d middlewares, got %d", 3, len(api.middlewares))
+	}
+}
+
+func TestWithTransport(t *testing.T) {
+	calls := 0
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       http.NoBody,
+			Request:    req,
+		}, nil
+	})
+	client := &http.Client{}
+	api := New(WithURL("http://whale.invalid"), WithAccessKey("KEY"), WithHTTPClient(client), WithTransport(transport))
+	if client.Transport != nil {
+		t.Errorf("Expected http client not to be modified")
+	}
+	get[response](context.Background(), *api, "/ok", nil)
+	if calls != 1 {
+		t.Errorf("Expected %d call, got %d", 1, calls)
+	}
+}
+
+func TestBuiltInMiddlewares(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"response": "` + r.Header.Get("User-Agent") + " " + r.Header.Get("X-Request-ID") + `"}`))
+	}))
+	defer server.Close()
+
+	var dump bytes.Buffer
+	api := New(
+		WithURL(server.URL),
+		WithAccessKey("SECRET"),
+		WithMiddleware(UserAgentMiddleware("whales/1.0"), RequestIDMiddleware(""), DumpMiddleware(&dump)),
+	)
+	ctx := ContextWithRequestID(context.Background(), "req-1")
+	res, err := get[response](ctx, *api, "/ok", []APIArgument{{Key: "api_key", Value: "SECRET"}})
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err)
+	}
+	if res.Response != "whales/1.0 req-1" {
+		t.Errorf("Expected %s, got %s", "whales/1.0 req-1", res.Response)
+	}
+	if strings.Contains(dump.String(), "SECRET") {
+		t.Errorf("Expected access key to be redacted, got %s", dump.String())
+	}
+	if !strings.Contains(dump.String(), "X-Wa-Api-Key: REDACTED") || !strings.Contains(dump.String(), "X-Request-Id: req-1") || !strings.Contains(dump.String(), "whales/1.0 req-1") {
+		t.Errorf("Expected request and response in dump, got %s", dump.String())
+	}
+
+	res, err = get[response](context.Background(), *api, "/ok", nil)
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err)
+	}
+	if id := strings.TrimPrefix(res.Response, "whales/1.0 "); len(id) != 32 {
+		t.Errorf("Expected random request ID, got %s", id)
+	}
+}
+
+type failingBody struct {
+	closed bool
+}
+
+func (b *failingBody) Read([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func (b *failingBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestDumpMiddlewareClosesBody(t *testing.T) {
+	body := &failingBody{}
+	next := func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: body}, nil
+	}
+	var dump bytes.Buffer
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/ok", nil)
+	_, err := DumpMiddleware(&dump)(next)(req)
+	if err == nil {
+		t.Errorf("Expected error, got nil")
+	}
+	if !body.closed {
+		t.Errorf("Expected response body to be closed")
+	}
+}
+
+func TestRequestIDMiddlewareRetries(t *testing.T) {
+	ids := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ids = append(ids, r.Header.Get("X-Request-ID"))
+		if len(ids)%2 == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"response": "OK!"}`))
+	}))
+	defer server.Close()
+
+	policy := DefaultRetryPolicy()
+	policy.BaseBackoff = time.Millisecond
+	api := New(WithURL(server.URL), WithAccessKey("OK"), WithRetryPolicy(policy), WithMiddleware(RequestIDMiddleware("")))
+	for i := 0; i < 2; i++ {
+		if _, err := get[response](context.Background(), *api, "/ok", nil); err != nil {
+			t.Fatalf("Expected nil, got %s", err)
+		}
+	}
+	if len(ids) != 4 || ids[0] == "" || ids[0] != ids[1] || ids[2] != ids[3] {
+		t.Fatalf("Expected attempts of a call to share ID, got %v", ids)
+	}
+	if ids[0] == ids[2] {
+		t.Errorf("Expected calls to have different IDs, got %v", ids)
+	}
+}
@@ -0,0 +1,133 @@
+package whalealertapi
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// Observer is notified about every attempt of every request, it can be used to collect metrics and traces
+// Methods are called synchronously, so they must be fast and safe for concurrent use
+type Observer interface {
+	// OnRequest is called before attempt is sent, returned context is used for the attempt
+	OnRequest(ctx context.Context, info RequestInfo) context.Context
+	// OnResponse is called after attempt, with ctx returned by OnRequest
+	OnResponse(ctx context.Context, info RequestInfo, res ResponseInfo)
+	// OnRetry is called when failed attempt will be repeated after delay
+	OnRetry(ctx context.Context, info RequestInfo, delay time.Duration)
+	// OnRateLimitWait is called after attempt waited for client rate limiter
+	OnRateLimitWait(ctx context.Context, endpoint string, wait time.Duration)
+}
+
+// RequestInfo describes single attempt of a request
+type RequestInfo struct {
+	Method string
+	// Endpoint is name of endpoint without parameters, like "/status", "/transaction" or "/transactions"
+	Endpoint string
+	// Path is endpoint with path parameters, like "/transaction/bitcoin/hash"
+	Path    string
+	Attempt int
+	// Span is trace span of the attempt, its traceparent is sent with the request
+	Span    SpanContext
+	Started time.Time
+}
+
+// ResponseInfo describes result of single attempt
+type ResponseInfo struct {
+	// StatusCode is 0 when no response was received
+	StatusCode int
+	// Bytes is number of read response body bytes
+	Bytes    int64
+	Duration time.Duration
+	// Err is error of the attempt, including ErrorResponse returned by the API
+	Err error
+}
+
+// Failed returns true when attempt ended with error
+func (r ResponseInfo) Failed() bool {
+	return r.Err != nil || r.StatusCode == 0 || r.StatusCode >= 400
+}
+
+// NopObserver is Observer doing nothing, it can be embedded to implement only some methods
+type NopObserver struct{}
+
+func (NopObserver) OnRequest(ctx context.Context, info RequestInfo) context.Context { return ctx }
+
+func (NopObserver) OnResponse(ctx context.Context, info RequestInfo, res ResponseInfo) {}
+
+func (NopObserver) OnRetry(ctx context.Context, info RequestInfo, delay time.Duration) {}
+
+func (NopObserver) OnRateLimitWait(ctx context.Context, endpoint string, wait time.Duration) {}
+
+// WithObserver sets observer notified about requests, nil removes it
+// When observer is set, every attempt has trace span, a child of span from ContextWithSpan or a new trace
+func WithObserver(observer Observer) Option {
+	return func(api *WhaleAlertAPI) {
+		api.observer = observer
+	}
+}
+
+// endpointName returns first segment of endpoint path, so metrics don't depend on path parameters
+func endpointName(endpoint string) string {
+	name := strings.TrimLeft(endpoint, "/")
+	if i := strings.IndexByte(name, '/'); i >= 0 {
+		name = name[:i]
+	}
+	return "/" + name
+}
+
+// waitLimiter waits for api rate limiter and reports the wait to observer
+func (api WhaleAlertAPI) waitLimiter(ctx context.Context, endpoint string) error {
+	if api.limiter == nil {
+		return nil
+	}
+	started := time.Now()
+	err := api.limiter.Wait(ctx)
+	if api.observer != nil {
+		api.observer.OnRateLimitWait(ctx, endpointName(endpoint), time.Since(started))
+	}
+	return err
+}
+
+// startAttempt logs attempt, starts its span and notifies observer
+func (api WhaleAlertAPI) startAttempt(ctx context.Context, method, endpoint, requestURL string, attempt int) (context.Context, RequestInfo) {
+	info := RequestInfo{
+		Method:   method,
+		Endpoint: endpointName(endpoint),
+		Path:     endpoint,
+		Attempt:  attempt,
+		Started:  time.Now(),
+	}
+	api.logRequest(ctx, method, endpoint, requestURL, attempt)
+	parent, ok := SpanFromContext(ctx)
+	switch {
+	case ok:
+		info.Span = parent.child()
+	case api.observer != nil:
+		info.Span = NewSpanContext()
+	default:
+		return ctx, info
+	}
+	ctx = ContextWithSpan(ctx, info.Span)
+	if api.observer != nil {
+		ctx = api.observer.OnRequest(ctx, info)
+	}
+	return ctx, info
+}
+
+// finishAttempt logs result of attempt and notifies observer
+func (api WhaleAlertAPI) finishAttempt(ctx context.Context, info RequestInfo, status int, bytes int64, err error) {
+	latency := time.Since(info.Started)
+	api.logResponse(ctx, info.Path, info.Attempt, latency, status, bytes, err)
+	if api.observer != nil {
+		api.observer.OnResponse(ctx, info, ResponseInfo{StatusCode: status, Bytes: bytes, Duration: latency, Err: err})
+	}
+}
+
+// retrying logs that attempt will be repeated and notifies observer
+func (api WhaleAlertAPI) retrying(ctx context.Context, info RequestInfo, delay time.Duration, reason string, value any) {
+	api.logRetry(ctx, info.Path, info.Attempt, delay, reason, value)
+	if api.observer != nil {
+		api.observer.OnRetry(ctx, info, delay)
+	}
+}
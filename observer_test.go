@@ -0,0 +1,166 @@
+package whalealertapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingObserver records names of called methods
+type recordingObserver struct {
+	mu     sync.Mutex
+	calls  []string
+	infos  []RequestInfo
+	status []int
+}
+
+func (o *recordingObserver) OnRequest(ctx context.Context, info RequestInfo) context.Context {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.calls = append(o.calls, "request "+info.Endpoint)
+	o.infos = append(o.infos, info)
+	return ctx
+}
+
+func (o *recordingObserver) OnResponse(ctx context.Context, info RequestInfo, res ResponseInfo) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.calls = append(o.calls, "response "+info.Endpoint)
+	o.status = append(o.status, res.StatusCode)
+}
+
+func (o *recordingObserver) OnRetry(ctx context.Context, info RequestInfo, delay time.Duration) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.calls = append(o.calls, "retry "+info.Endpoint)
+}
+
+func (o *recordingObserver) OnRateLimitWait(ctx context.Context, endpoint string, wait time.Duration) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.calls = append(o.calls, "wait "+endpoint)
+}
+
+func TestObserver(t *testing.T) {
+	calls := 0
+	traceparents := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		traceparents = append(traceparents, r.Header.Get("traceparent"))
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"result":"success","count":1,"transactions":[{"blockchain":"bitcoin","hash":"abc"}]}`))
+	}))
+	defer server.Close()
+
+	observer := &recordingObserver{}
+	policy := DefaultRetryPolicy()
+	policy.BaseBackoff = time.Millisecond
+	api := New(WithURL(server.URL), WithAccessKey("X"), WithRetryPolicy(policy), WithRateLimit(10, time.Second), WithObserver(observer))
+
+	parent, _ := ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	if _, err := api.TransactionContext(ContextWithSpan(context.Background(), parent), "bitcoin", "abc"); err != nil {
+		t.Fatalf("Expected nil, got %s", err)
+	}
+	want := []string{
+		"wait /transaction", "request /transaction", "response /transaction", "retry /transaction",
+		"wait /transaction", "request /transaction", "response /transaction",
+	}
+	if strings.Join(observer.calls, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected %v, got %v", want, observer.calls)
+	}
+	if observer.status[0] != http.StatusServiceUnavailable || observer.status[1] != http.StatusOK {
+		t.Errorf("Unexpected statuses %v", observer.status)
+	}
+	for i, info := range observer.infos {
+		if info.Attempt != i+1 || info.Path != "/transaction/bitcoin/abc" {
+			t.Errorf("Unexpected request info %+v", info)
+		}
+		// Every attempt is child span of the parent
+		if info.Span.TraceID != parent.TraceID || info.Span.SpanID == parent.SpanID {
+			t.Errorf("Expected child span of %s, got %s", parent.TraceParent(), info.Span.TraceParent())
+		}
+		if traceparents[i] != info.Span.TraceParent() {
+			t.Errorf("Expected traceparent %s, got %s", info.Span.TraceParent(), traceparents[i])
+		}
+	}
+
+	// New trace is started when context has no span
+	traceparents = nil
+	api.Status()
+	if span, err := ParseTraceParent(traceparents[0]); err != nil || span.TraceID == parent.TraceID {
+		t.Errorf("Expected new trace, got %s %v", traceparents[0], err)
+	}
+}
+
+func TestParseTraceParent(t *testing.T) {
+	tests := []struct {
+		value   string
+		valid   bool
+		sampled bool
+	}{
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", true, true},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", true, false},
+		{"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-future", true, true},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", false, false},
+		{"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", false, false},
+		{"00-00000000000000000000000000000000-00f067aa0ba902b7-01", false, false},
+		{"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", false, false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902-01", false, false},
+		{"", false, false},
+	}
+	for _, test := range tests {
+		span, err := ParseTraceParent(test.value)
+		if (err == nil) != test.valid || span.Sampled != test.sampled && test.valid {
+			t.Errorf("%s: expected valid %t sampled %t, got %+v %v", test.value, test.valid, test.sampled, span, err)
+		}
+		if test.valid && strings.HasPrefix(test.value, "00") && span.TraceParent() != test.value {
+			t.Errorf("Expected %s, got %s", test.value, span.TraceParent())
+		}
+	}
+}
+
+func TestPrometheusObserver(t *testing.T) {
+	observer := NewPrometheusObserver(0.1, 1)
+	ctx := context.Background()
+	status := RequestInfo{Endpoint: "/status"}
+	transaction := RequestInfo{Endpoint: "/transaction"}
+	observer.OnResponse(ctx, status, ResponseInfo{StatusCode: 200, Duration: 50 * time.Millisecond})
+	observer.OnResponse(ctx, status, ResponseInfo{StatusCode: 503, Duration: 500 * time.Millisecond})
+	observer.OnResponse(ctx, transaction, ResponseInfo{Duration: 2 * time.Second, Err: context.DeadlineExceeded})
+	observer.OnRetry(ctx, status, time.Second)
+	observer.OnRateLimitWait(ctx, "/status", 1500*time.Millisecond)
+
+	recorder := httptest.NewRecorder()
+	observer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if ct := recorder.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Unexpected content type %s", ct)
+	}
+	out := recorder.Body.String()
+	for _, want := range []string{
+		"# TYPE whalealert_requests_total counter\n",
+		`whalealert_requests_total{endpoint="/status",code="200"} 1` + "\n",
+		`whalealert_requests_total{endpoint="/status",code="503"} 1` + "\n",
+		`whalealert_requests_total{endpoint="/transaction",code="error"} 1` + "\n",
+		`whalealert_request_errors_total{endpoint="/status"} 1` + "\n",
+		`whalealert_request_errors_total{endpoint="/transaction"} 1` + "\n",
+		"# TYPE whalealert_request_duration_seconds histogram\n",
+		`whalealert_request_duration_seconds_bucket{endpoint="/status",le="0.1"} 1` + "\n",
+		`whalealert_request_duration_seconds_bucket{endpoint="/status",le="1"} 2` + "\n",
+		`whalealert_request_duration_seconds_bucket{endpoint="/status",le="+Inf"} 2` + "\n",
+		`whalealert_request_duration_seconds_sum{endpoint="/status"} 0.55` + "\n",
+		`whalealert_request_duration_seconds_count{endpoint="/transaction"} 1` + "\n",
+		`whalealert_retries_total{endpoint="/status"} 1` + "\n",
+		`whalealert_rate_limit_wait_seconds_total{endpoint="/status"} 1.5` + "\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in metrics, got %s", want, out)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package whalealertapi
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// DefaultURL is base url of Whale Alert API
+const DefaultURL = "https://api.whale-alert.io/v1"
+
+// DefaultTimeout is timeout of single attempt used by default http client
+const DefaultTimeout = 30 * time.Second
+
+// Option configures client created by New or derived with With
+type Option func(*WhaleAlertAPI)
+
+// WithURL sets base url of the API
+func WithURL(url string) Option {
+	return func(api *WhaleAlertAPI) {
+		api.url = url
+	}
+}
+
+// WithAccessKey sets API access key
+func WithAccessKey(key string) Option {
+	return func(api *WhaleAlertAPI) {
+		api.key = key
+	}
+}
+
+// WithHTTPClient sets http client used to send requests
+func WithHTTPClient(client *http.Client) Option {
+	return func(api *WhaleAlertAPI) {
+		api.client = client
+	}
+}
+
+// WithTimeout sets timeout of single attempt, including reading the response body
+// Http client set with WithHTTPClient is not modified, a copy is used instead
+func WithTimeout(timeout time.Duration) Option {
+	return func(api *WhaleAlertAPI) {
+		api.timeout = timeout
+	}
+}
+
+// WithUserAgent sets User-Agent header sent with every request
+func WithUserAgent(userAgent string) Option {
+	return func(api *WhaleAlertAPI) {
+		api.userAgent = userAgent
+	}
+}
+
+// WithRateLimit limits number of requests to n per given period, see RateLimiter
+func WithRateLimit(n int, per time.Duration) Option {
+	return func(api *WhaleAlertAPI) {
+		api.limiter = NewRateLimiter(n, per)
+	}
+}
+
+// WithRateLimiter sets limiter used by the client, it allows sharing one limiter between many clients
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(api *WhaleAlertAPI) {
+		api.limiter = limiter
+	}
+}
+
+// WithRetryPolicy sets policy used to repeat failed requests
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(api *WhaleAlertAPI) {
+		api.retry = policy
+	}
+}
+
+// WithLogger sets logger used by the client, nil disables logging
+func WithLogger(logger *slog.Logger) Option {
+	return func(api *WhaleAlertAPI) {
+		api.logger = logger
+	}
+}
+
+// WithPlan sets plan which limits are used to validate requests
+func WithPlan(plan Plan) Option {
+	return func(api *WhaleAlertAPI) {
+		api.plan = plan
+	}
+}
+
+// WithValidation enables or disables validation of requests before they are sent, it is enabled by default
+func WithValidation(enabled bool) Option {
+	return func(api *WhaleAlertAPI) {
+		api.skipValidation = !enabled
+	}
+}
+
+// apply applies options and prepares http client
+func (api *WhaleAlertAPI) apply(opts []Option) {
+	for _, opt := range opts {
+		opt(api)
+	}
+	if api.client == nil {
+		api.client = &http.Client{Timeout: DefaultTimeout}
+	}
+	timeoutChanged := api.timeout > 0 && api.client.Timeout != api.timeout
+	transportChanged := api.transport != nil && api.client.Transport != api.transport
+	if timeoutChanged || transportChanged {
+		client := *api.client
+		if timeoutChanged {
+			client.Timeout = api.timeout
+		}
+		if transportChanged {
+			client.Transport = api.transport
+		}
+		api.client = &client
+	}
+}
@@ -0,0 +1,119 @@
+package whalealertapi
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewOptions(t *testing.T) {
+	api := New()
+	if api.url != DefaultURL || api.client.Timeout != DefaultTimeout || api.plan != DefaultPlan {
+		t.Errorf("Unexpected defaults %+v", api)
+	}
+
+	client := &http.Client{}
+	limiter := NewRateLimiter(5, time.Second)
+	api = New(
+		WithURL("http://localhost"),
+		WithAccessKey("KEY"),
+		WithHTTPClient(client),
+		WithTimeout(time.Second),
+		WithUserAgent("tests"),
+		WithRateLimiter(limiter),
+		WithRetryPolicy(DefaultRetryPolicy()),
+		WithPlan(PlanFree),
+		WithValidation(false),
+	)
+	if api.url != "http://localhost" || api.key != "KEY" || api.userAgent != "tests" || api.limiter != limiter {
+		t.Errorf("Options were not applied %+v", api)
+	}
+	if api.retry.MaxAttempts != 3 || api.plan != PlanFree || !api.skipValidation {
+		t.Errorf("Options were not applied %+v", api)
+	}
+	if api.client == client || api.client.Timeout != time.Second || client.Timeout != 0 {
+		t.Errorf("Expected copy of http client with timeout, got %+v", api.client)
+	}
+}
+
+func TestWith(t *testing.T) {
+	api := New(WithAccessKey("KEY"), WithRateLimit(10, time.Minute))
+	derived := api.With(WithAccessKey("OTHER"), WithTimeout(time.Second))
+	if api.key != "KEY" || api.client.Timeout != DefaultTimeout {
+		t.Errorf("Expected original client not to be modified, got %+v", api)
+	}
+	if derived.key != "OTHER" || derived.client.Timeout != time.Second {
+		t.Errorf("Options were not applied %+v", derived)
+	}
+	if derived.limiter != api.limiter {
+		t.Errorf("Expected derived client to share rate limiter")
+	}
+}
+
+func TestConcurrentUse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("User-Agent") != "tests" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"result":"error","message":"missing user agent"}`))
+			return
+		}
+		w.Write([]byte(`{"response": "` + r.Header.Get("X-WA-API-KEY") + `"}`))
+	}))
+	defer server.Close()
+
+	api := New(WithURL(server.URL), WithAccessKey("KEY"), WithUserAgent("tests"))
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			res, err := get[response](context.Background(), *api, "/ok", nil)
+			if err != nil || res.Response != "KEY" {
+				t.Errorf("Expected KEY, got (%v, %v)", res, err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			res, err := get[response](context.Background(), *api.With(WithAccessKey("OTHER")), "/ok", nil)
+			if err != nil || res.Response != "OTHER" {
+				t.Errorf("Expected OTHER, got (%v, %v)", res, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestLoggerRetries(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"response": "OK!"}`))
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	policy := DefaultRetryPolicy()
+	policy.BaseBackoff = time.Millisecond
+	api := New(
+		WithURL(server.URL),
+		WithAccessKey("KEY"),
+		WithRetryPolicy(policy),
+		WithLogger(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))),
+	)
+	if _, err := get[response](context.Background(), *api, "/ok", nil); err != nil {
+		t.Fatalf("Expected nil, got %s", err)
+	}
+	if !strings.Contains(logs.String(), "retrying request") || !strings.Contains(logs.String(), "status=503") {
+		t.Errorf("Expected retry to be logged, got %s", logs.String())
+	}
+}
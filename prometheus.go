@@ -0,0 +1,182 @@
+package whalealertapi
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultDurationBuckets are upper bounds of request duration histogram buckets in seconds
+var DefaultDurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// PrometheusObserver is Observer counting requests per endpoint
+// It is http.Handler serving the metrics in Prometheus text exposition format:
+//
+//	whalealert_requests_total{endpoint,code} - attempts by status code, code is "error" when no response was received
+//	whalealert_request_errors_total{endpoint} - failed attempts
+//	whalealert_request_duration_seconds{endpoint} - histogram of attempt durations
+//	whalealert_retries_total{endpoint} - repeated attempts
+//	whalealert_rate_limit_wait_seconds_total{endpoint} - time spent waiting for client rate limiter
+type PrometheusObserver struct {
+	NopObserver
+
+	mu        sync.Mutex
+	buckets   []float64
+	requests  map[[2]string]uint64
+	errors    map[string]uint64
+	durations map[string]*histogram
+	retries   map[string]uint64
+	waits     map[string]float64
+}
+
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// NewPrometheusObserver returns observer with given duration buckets, DefaultDurationBuckets are used when none are given
+func NewPrometheusObserver(buckets ...float64) *PrometheusObserver {
+	if len(buckets) == 0 {
+		buckets = DefaultDurationBuckets
+	}
+	buckets = append([]float64{}, buckets...)
+	sort.Float64s(buckets)
+	return &PrometheusObserver{
+		buckets:   buckets,
+		requests:  map[[2]string]uint64{},
+		errors:    map[string]uint64{},
+		durations: map[string]*histogram{},
+		retries:   map[string]uint64{},
+		waits:     map[string]float64{},
+	}
+}
+
+func (o *PrometheusObserver) OnResponse(ctx context.Context, info RequestInfo, res ResponseInfo) {
+	code := "error"
+	if res.StatusCode != 0 {
+		code = strconv.Itoa(res.StatusCode)
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.requests[[2]string{info.Endpoint, code}]++
+	if res.Failed() {
+		o.errors[info.Endpoint]++
+	}
+	h, ok := o.durations[info.Endpoint]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(o.buckets))}
+		o.durations[info.Endpoint] = h
+	}
+	seconds := res.Duration.Seconds()
+	for i, bound := range o.buckets {
+		if seconds <= bound {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += seconds
+}
+
+func (o *PrometheusObserver) OnRetry(ctx context.Context, info RequestInfo, delay time.Duration) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.retries[info.Endpoint]++
+}
+
+func (o *PrometheusObserver) OnRateLimitWait(ctx context.Context, endpoint string, wait time.Duration) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.waits[endpoint] += wait.Seconds()
+}
+
+// ServeHTTP writes metrics in Prometheus text exposition format
+func (o *PrometheusObserver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	o.WriteTo(w)
+}
+
+// WriteTo writes metrics in Prometheus text exposition format to w
+func (o *PrometheusObserver) WriteTo(w io.Writer) (int64, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	b := bufio.NewWriter(w)
+	var n int64
+	write := func(format string, args ...any) {
+		written, _ := fmt.Fprintf(b, format, args...)
+		n += int64(written)
+	}
+
+	write("# HELP whalealert_requests_total Number of requests sent to Whale Alert API.\n")
+	write("# TYPE whalealert_requests_total counter\n")
+	keys := make([][2]string, 0, len(o.requests))
+	for key := range o.requests {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+	for _, key := range keys {
+		write("whalealert_requests_total{endpoint=\"%s\",code=\"%s\"} %d\n", escapeLabel(key[0]), escapeLabel(key[1]), o.requests[key])
+	}
+
+	write("# HELP whalealert_request_errors_total Number of failed requests sent to Whale Alert API.\n")
+	write("# TYPE whalealert_request_errors_total counter\n")
+	for _, endpoint := range sortedKeys(o.errors) {
+		write("whalealert_request_errors_total{endpoint=\"%s\"} %d\n", escapeLabel(endpoint), o.errors[endpoint])
+	}
+
+	write("# HELP whalealert_request_duration_seconds Duration of requests sent to Whale Alert API.\n")
+	write("# TYPE whalealert_request_duration_seconds histogram\n")
+	for _, endpoint := range sortedKeys(o.durations) {
+		h := o.durations[endpoint]
+		label := escapeLabel(endpoint)
+		for i, bound := range o.buckets {
+			write("whalealert_request_duration_seconds_bucket{endpoint=\"%s\",le=\"%s\"} %d\n", label, formatFloat(bound), h.counts[i])
+		}
+		write("whalealert_request_duration_seconds_bucket{endpoint=\"%s\",le=\"+Inf\"} %d\n", label, h.count)
+		write("whalealert_request_duration_seconds_sum{endpoint=\"%s\"} %s\n", label, formatFloat(h.sum))
+		write("whalealert_request_duration_seconds_count{endpoint=\"%s\"} %d\n", label, h.count)
+	}
+
+	write("# HELP whalealert_retries_total Number of repeated requests.\n")
+	write("# TYPE whalealert_retries_total counter\n")
+	for _, endpoint := range sortedKeys(o.retries) {
+		write("whalealert_retries_total{endpoint=\"%s\"} %d\n", escapeLabel(endpoint), o.retries[endpoint])
+	}
+
+	write("# HELP whalealert_rate_limit_wait_seconds_total Time spent waiting for client rate limiter.\n")
+	write("# TYPE whalealert_rate_limit_wait_seconds_total counter\n")
+	for _, endpoint := range sortedKeys(o.waits) {
+		write("whalealert_rate_limit_wait_seconds_total{endpoint=\"%s\"} %s\n", escapeLabel(endpoint), formatFloat(o.waits[endpoint]))
+	}
+	return n, b.Flush()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// escapeLabel escapes label value as required by text exposition format
+func escapeLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,135 @@
+package whalealertapi
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// QuotaState is API usage reported by rate limit headers of the last response
+type QuotaState struct {
+	// Limit is number of requests allowed in current period
+	Limit int
+	// Remaining is number of requests left in current period
+	Remaining int
+	// Reset is time when current period ends, it is zero when not reported
+	Reset time.Time
+	// UpdatedAt is time of the last response with rate limit headers, zero when nothing was reported yet
+	UpdatedAt time.Time
+}
+
+// Known returns true when API reported the quota at least once
+func (q QuotaState) Known() bool {
+	return !q.UpdatedAt.IsZero()
+}
+
+// Usage returns used fraction of the quota, from 0 to 1
+func (q QuotaState) Usage() float64 {
+	if q.Limit <= 0 {
+		return 0
+	}
+	used := float64(q.Limit-q.Remaining) / float64(q.Limit)
+	if used < 0 {
+		return 0
+	}
+	if used > 1 {
+		return 1
+	}
+	return used
+}
+
+// quotaTracker keeps QuotaState shared by all copies of the client
+type quotaTracker struct {
+	mu         sync.Mutex
+	state      QuotaState
+	thresholds []float64
+	crossed    []bool
+	callback   func(QuotaState, float64)
+}
+
+// WithQuotaThresholds calls callback when quota usage (see QuotaState.Usage) reaches one of thresholds
+// Callback is called once per threshold, again only after usage drops below it
+func WithQuotaThresholds(callback func(state QuotaState, threshold float64), thresholds ...float64) Option {
+	return func(api *WhaleAlertAPI) {
+		thresholds = append([]float64{}, thresholds...)
+		sort.Float64s(thresholds)
+		api.quota = &quotaTracker{
+			thresholds: thresholds,
+			crossed:    make([]bool, len(thresholds)),
+			callback:   callback,
+		}
+	}
+}
+
+// Quota returns API usage reported by the last response
+func (api WhaleAlertAPI) Quota() QuotaState {
+	if api.quota == nil {
+		return QuotaState{}
+	}
+	api.quota.mu.Lock()
+	defer api.quota.mu.Unlock()
+	return api.quota.state
+}
+
+// observeQuota updates quota from response headers and adapts rate limiter to it
+func (api WhaleAlertAPI) observeQuota(header http.Header) {
+	state, ok := parseQuota(header, time.Now())
+	if !ok {
+		return
+	}
+	if api.quota != nil {
+		api.quota.update(state)
+	}
+	if api.limiter != nil {
+		api.limiter.adapt(state.Remaining, state.Reset)
+	}
+}
+
+// update replaces state and calls callback for crossed thresholds
+func (t *quotaTracker) update(state QuotaState) {
+	t.mu.Lock()
+	t.state = state
+	usage := state.Usage()
+	crossed := []float64{}
+	for i, threshold := range t.thresholds {
+		if usage >= threshold && !t.crossed[i] {
+			crossed = append(crossed, threshold)
+		}
+		t.crossed[i] = usage >= threshold
+	}
+	t.mu.Unlock()
+	if t.callback == nil {
+		return
+	}
+	for _, threshold := range crossed {
+		t.callback(state, threshold)
+	}
+}
+
+// parseQuota reads X-RateLimit-* or RateLimit-* headers
+// Reset is accepted as seconds until reset or as unix timestamp
+func parseQuota(header http.Header, now time.Time) (QuotaState, bool) {
+	for _, prefix := range []string{"X-RateLimit-", "RateLimit-"} {
+		limit, err := strconv.Atoi(header.Get(prefix + "Limit"))
+		if err != nil {
+			continue
+		}
+		remaining, err := strconv.Atoi(header.Get(prefix + "Remaining"))
+		if err != nil {
+			continue
+		}
+		state := QuotaState{Limit: limit, Remaining: remaining, UpdatedAt: now}
+		if reset, err := strconv.ParseInt(header.Get(prefix+"Reset"), 10, 64); err == nil && reset >= 0 {
+			// Values bigger than a year in seconds are unix timestamps
+			if reset > 365*24*3600 {
+				state.Reset = time.Unix(reset, 0)
+			} else {
+				state.Reset = now.Add(time.Duration(reset) * time.Second)
+			}
+		}
+		return state, true
+	}
+	return QuotaState{}, false
+}
@@ -0,0 +1,121 @@
+package whalealertapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseQuota(t *testing.T) {
+	now := time.Date(2023, 3, 25, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		header http.Header
+		ok     bool
+		want   QuotaState
+	}{
+		{"none", http.Header{}, false, QuotaState{}},
+		{"x-ratelimit", http.Header{
+			"X-Ratelimit-Limit":     {"60"},
+			"X-Ratelimit-Remaining": {"15"},
+			"X-Ratelimit-Reset":     {"30"},
+		}, true, QuotaState{Limit: 60, Remaining: 15, Reset: now.Add(30 * time.Second), UpdatedAt: now}},
+		{"ratelimit unix reset", http.Header{
+			"Ratelimit-Limit":     {"10"},
+			"Ratelimit-Remaining": {"0"},
+			"Ratelimit-Reset":     {strconv.FormatInt(now.Add(time.Minute).Unix(), 10)},
+		}, true, QuotaState{Limit: 10, Remaining: 0, Reset: now.Add(time.Minute), UpdatedAt: now}},
+		{"no reset", http.Header{
+			"X-Ratelimit-Limit":     {"10"},
+			"X-Ratelimit-Remaining": {"5"},
+		}, true, QuotaState{Limit: 10, Remaining: 5, UpdatedAt: now}},
+		{"malformed", http.Header{
+			"X-Ratelimit-Limit":     {"ten"},
+			"X-Ratelimit-Remaining": {"5"},
+		}, false, QuotaState{}},
+	}
+	for _, tt := range tests {
+		got, ok := parseQuota(tt.header, now)
+		if ok != tt.ok || !got.Reset.Equal(tt.want.Reset) || got.Limit != tt.want.Limit ||
+			got.Remaining != tt.want.Remaining || !got.UpdatedAt.Equal(tt.want.UpdatedAt) {
+			t.Errorf("%s: expected %+v %t, got %+v %t", tt.name, tt.want, tt.ok, got, ok)
+		}
+	}
+
+	state := QuotaState{Limit: 10, Remaining: 2}
+	if got := state.Usage(); got != 0.8 {
+		t.Errorf("Expected usage %f, got %f", 0.8, got)
+	}
+	if (QuotaState{}).Known() {
+		t.Errorf("Expected empty quota to be unknown")
+	}
+}
+
+func TestQuota(t *testing.T) {
+	var remaining atomic.Int32
+	remaining.Store(10)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "10")
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(int(remaining.Add(-1))))
+		w.Header().Set("X-RateLimit-Reset", "60")
+		w.Write([]byte(`{"result":"success","blockchain_count":0}`))
+	}))
+	defer ts.Close()
+
+	crossed := []float64{}
+	api := New(WithURL(ts.URL), WithAccessKey("X"),
+		WithQuotaThresholds(func(state QuotaState, threshold float64) {
+			crossed = append(crossed, threshold)
+		}, 0.5, 0.2))
+	if api.Quota().Known() {
+		t.Errorf("Expected quota to be unknown before first request")
+	}
+	for i := 0; i < 6; i++ {
+		if _, err := api.Status(); err != nil {
+			t.Fatalf("Expected nil, got %s", err)
+		}
+	}
+	quota := api.Quota()
+	if quota.Limit != 10 || quota.Remaining != 4 || quota.Reset.IsZero() {
+		t.Errorf("Unexpected quota %+v", quota)
+	}
+	// Each threshold is reported once
+	if len(crossed) != 2 || crossed[0] != 0.2 || crossed[1] != 0.5 {
+		t.Errorf("Expected thresholds %v, got %v", []float64{0.2, 0.5}, crossed)
+	}
+	if got := api.With(WithUserAgent("test")).Quota(); got != quota {
+		t.Errorf("Expected derived client to share quota, got %+v", got)
+	}
+}
+
+func TestRateLimiterAdapt(t *testing.T) {
+	now := time.Date(2023, 3, 25, 12, 0, 0, 0, time.UTC)
+	l := NewRateLimiter(10, time.Minute)
+	l.now = func() time.Time { return now }
+	l.last = now
+
+	l.adapt(3, time.Time{})
+	if got := l.Tokens(); got != 3 {
+		t.Errorf("Expected %d tokens, got %f", 3, got)
+	}
+	// Bigger remaining than tokens does not add any
+	l.adapt(8, time.Time{})
+	if got := l.Tokens(); got != 3 {
+		t.Errorf("Expected %d tokens, got %f", 3, got)
+	}
+	// Nothing remains, so next request waits for reset
+	l.adapt(0, now.Add(30*time.Second))
+	if got := l.WaitTime(); got != 30*time.Second {
+		t.Errorf("Expected %s wait, got %s", 30*time.Second, got)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx); err == nil {
+		t.Errorf("Expected wait to be cut by context")
+	}
+}
@@ -0,0 +1,131 @@
+package whalealertapi
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter is token bucket limiter which throttles requests sent to the API
+// It is safe for concurrent use, all copies of WhaleAlertAPI share the same limiter
+type RateLimiter struct {
+	mu       sync.Mutex
+	capacity float64
+	tokens   float64
+	interval time.Duration
+	last     time.Time
+	now      func() time.Time
+}
+
+// NewRateLimiter returns limiter which allows n requests per given period
+// Bucket starts full, so up to n requests can be sent at once
+func NewRateLimiter(n int, per time.Duration) *RateLimiter {
+	if n < 1 {
+		n = 1
+	}
+	if per <= 0 {
+		per = time.Minute
+	}
+	l := &RateLimiter{
+		capacity: float64(n),
+		tokens:   float64(n),
+		interval: per / time.Duration(n),
+		now:      time.Now,
+	}
+	l.last = l.now()
+	return l
+}
+
+// refill adds tokens which were generated since last refill, must be called with lock held
+func (l *RateLimiter) refill() {
+	now := l.now()
+	elapsed := now.Sub(l.last)
+	if elapsed <= 0 {
+		return
+	}
+	l.last = now
+	l.tokens += float64(elapsed) / float64(l.interval)
+	if l.tokens > l.capacity {
+		l.tokens = l.capacity
+	}
+}
+
+// reserve takes single token and returns time after which it may be used
+func (l *RateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill()
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens * float64(l.interval))
+}
+
+// cancel returns reserved token to the bucket
+func (l *RateLimiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens++
+	if l.tokens > l.capacity {
+		l.tokens = l.capacity
+	}
+}
+
+// adapt lowers number of tokens to remaining requests reported by the API
+// When nothing remains, next token is available at reset
+func (l *RateLimiter) adapt(remaining int, reset time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill()
+	if float64(remaining) < l.tokens {
+		l.tokens = float64(remaining)
+	}
+	if remaining > 0 || reset.IsZero() {
+		return
+	}
+	if wait := reset.Sub(l.now()); wait > 0 {
+		tokens := 1 - float64(wait)/float64(l.interval)
+		if tokens < l.tokens {
+			l.tokens = tokens
+		}
+	}
+}
+
+// Wait blocks until request may be sent or ctx is done
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	wait := l.reserve()
+	if wait == 0 {
+		return nil
+	}
+	if err := sleepContext(ctx, wait); err != nil {
+		l.cancel()
+		return err
+	}
+	return nil
+}
+
+// Tokens returns number of requests which can be sent right now without waiting
+func (l *RateLimiter) Tokens() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill()
+	if l.tokens < 0 {
+		return 0
+	}
+	return l.tokens
+}
+
+// WaitTime returns how long next request would wait for a token
+func (l *RateLimiter) WaitTime() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill()
+	if l.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - l.tokens) * float64(l.interval))
+}
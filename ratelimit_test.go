@@ -0,0 +1,94 @@
+package whalealertapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterTokens(t *testing.T) {
+	now := time.Date(2023, 3, 25, 12, 0, 0, 0, time.UTC)
+	l := NewRateLimiter(10, time.Minute)
+	l.now = func() time.Time { return now }
+	l.last = now
+
+	if got := l.Tokens(); got != 10 {
+		t.Errorf("Expected %d tokens, got %f", 10, got)
+	}
+	for i := 0; i < 10; i++ {
+		if wait := l.reserve(); wait != 0 {
+			t.Errorf("Expected no wait, got %s", wait)
+		}
+	}
+	if got := l.Tokens(); got != 0 {
+		t.Errorf("Expected %d tokens, got %f", 0, got)
+	}
+	if got := l.WaitTime(); got != 6*time.Second {
+		t.Errorf("Expected %s wait, got %s", 6*time.Second, got)
+	}
+	if wait := l.reserve(); wait != 6*time.Second {
+		t.Errorf("Expected %s wait, got %s", 6*time.Second, wait)
+	}
+	if got := l.WaitTime(); got != 12*time.Second {
+		t.Errorf("Expected %s wait, got %s", 12*time.Second, got)
+	}
+
+	now = now.Add(time.Hour)
+	if got := l.Tokens(); got != 10 {
+		t.Errorf("Expected bucket to be capped at %d tokens, got %f", 10, got)
+	}
+}
+
+func TestRateLimiterWaitContext(t *testing.T) {
+	l := NewRateLimiter(1, time.Hour)
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatalf("Expected nil, got %s", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected %s, got %v", context.DeadlineExceeded, err)
+	}
+	// Cancelled wait gives reserved token back
+	if got := l.WaitTime(); got > time.Hour {
+		t.Errorf("Expected wait of at most %s, got %s", time.Hour, got)
+	}
+}
+
+func TestGetSharesRateLimiter(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"response": "OK!"}`))
+	}))
+	defer server.Close()
+
+	api := New(WithURL(server.URL), WithAccessKey("OK"), WithRateLimit(3, time.Hour))
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var failed int32
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := get[response](ctx, *api, "/ok", []APIArgument{}); err != nil {
+				atomic.AddInt32(&failed, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	if calls != 3 {
+		t.Errorf("Expected %d calls, got %d", 3, calls)
+	}
+	if failed != 2 {
+		t.Errorf("Expected %d throttled calls, got %d", 2, failed)
+	}
+}
@@ -0,0 +1,177 @@
+package whalealertapi
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy describes when and how failed requests are repeated
+// Zero value disables retries
+type RetryPolicy struct {
+	// MaxAttempts is total number of attempts, including the first one
+	// Values lower than 2 disable retries
+	MaxAttempts int
+	// BaseBackoff is delay before second attempt, every next delay is doubled
+	BaseBackoff time.Duration
+	// MaxBackoff caps computed delay, zero means no cap
+	// When Retry-After asks for longer wait, request is not repeated and the response is returned
+	MaxBackoff time.Duration
+	// Jitter is fraction of delay (0-1) which is randomly subtracted from it
+	Jitter float64
+	// RetryStatusCodes lists HTTP status codes which should be retried
+	RetryStatusCodes []int
+	// RetryTransportError decides if error returned by http client should be retried
+	// When nil transport errors are not retried
+	RetryTransportError func(err error) bool
+}
+
+// DefaultRetryPolicy returns policy which retries rate limiting, server errors and transport errors
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseBackoff: 500 * time.Millisecond,
+		MaxBackoff:  30 * time.Second,
+		Jitter:      0.2,
+		RetryStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		RetryTransportError: IsTransientError,
+	}
+}
+
+// IsTransientError reports whether err may disappear when request is repeated
+// Only network errors, timeouts, unexpected EOF and reset or refused connections are transient,
+// context cancellation and deadline errors, TLS certificate errors and invalid requests are not
+func IsTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var certErr *tls.CertificateVerificationError
+	if errors.As(err, &certErr) {
+		return false
+	}
+	// http.Client wraps every error in url.Error, which is net.Error itself
+	var urlErr *url.Error
+	for errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// canRetry returns true when another attempt is allowed after given one
+func (p RetryPolicy) canRetry(attempt int) bool {
+	return attempt < p.MaxAttempts
+}
+
+// retryStatus returns true when response with given status code should be retried
+func (p RetryPolicy) retryStatus(code int) bool {
+	for _, c := range p.RetryStatusCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// retryError returns true when transport error should be retried
+func (p RetryPolicy) retryError(err error) bool {
+	return p.RetryTransportError != nil && p.RetryTransportError(err)
+}
+
+// backoff returns delay which should pass after given attempt
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseBackoff
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if p.MaxBackoff > 0 && delay >= p.MaxBackoff {
+			break
+		}
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	if p.Jitter > 0 {
+		jitter := p.Jitter
+		if jitter > 1 {
+			jitter = 1
+		}
+		delay -= time.Duration(float64(delay) * jitter * rand.Float64())
+	}
+	return delay
+}
+
+// delay returns time to wait before next attempt, Retry-After header takes precedence over backoff
+// It returns false when Retry-After is longer than MaxBackoff, request should not be repeated then
+func (p RetryPolicy) delay(attempt int, header http.Header) (time.Duration, bool) {
+	if d, ok := parseRetryAfter(header.Get("Retry-After"), time.Now()); ok {
+		if p.MaxBackoff > 0 && d > p.MaxBackoff {
+			return 0, false
+		}
+		return d, true
+	}
+	return p.backoff(attempt), true
+}
+
+// parseRetryAfter parses Retry-After header value which is either number of seconds or HTTP date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	d := date.Sub(now)
+	if d < 0 {
+		d = 0
+	}
+	return d, true
+}
+
+// sleepContext waits for given duration or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// discardBody reads rest of the body so connection can be reused, and closes it
+func discardBody(response *http.Response) {
+	io.Copy(io.Discard, io.LimitReader(response.Body, 64<<10))
+	response.Body.Close()
+}
@@ -0,0 +1,193 @@
+package whalealertapi
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	policy := RetryPolicy{BaseBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	expected := []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second,
+		time.Second,
+	}
+	for i, e := range expected {
+		if got := policy.backoff(i + 1); got != e {
+			t.Errorf("Attempt %d: expected %s, got %s", i+1, e, got)
+		}
+	}
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		got := policy.backoff(2)
+		if got < 100*time.Millisecond || got > 200*time.Millisecond {
+			t.Errorf("Expected backoff between 100ms and 200ms, got %s", got)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2023, 3, 25, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{"", 0, false},
+		{"3", 3 * time.Second, true},
+		{"-3", 0, false},
+		{"Sat, 25 Mar 2023 12:00:10 GMT", 10 * time.Second, true},
+		{"Sat, 25 Mar 2023 11:00:00 GMT", 0, true},
+		{"soon", 0, false},
+	}
+	for _, test := range tests {
+		got, ok := parseRetryAfter(test.value, now)
+		if got != test.expected || ok != test.ok {
+			t.Errorf("%q: expected (%s, %v), got (%s, %v)", test.value, test.expected, test.ok, got, ok)
+		}
+	}
+}
+
+func TestGetRetries(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&calls, 1) {
+		case 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"result":"error","message":"rate limit exceeded"}`))
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"result":"error","message":"unavailable"}`))
+		default:
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"response": "OK!"}`))
+		}
+	}))
+	defer server.Close()
+
+	policy := DefaultRetryPolicy()
+	policy.BaseBackoff = time.Millisecond
+	api := WhaleAlertAPI{client: &http.Client{}, url: server.URL, key: "OK", retry: policy}
+	res, err := get[response](context.Background(), api, "/ok", []APIArgument{})
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err)
+	}
+	if res.Response != "OK!" {
+		t.Errorf("Expected %s, got %s", "OK!", res.Response)
+	}
+	if calls != 3 {
+		t.Errorf("Expected %d calls, got %d", 3, calls)
+	}
+
+	// Attempts are limited
+	atomic.StoreInt32(&calls, 0)
+	api.retry.MaxAttempts = 2
+	_, err = get[response](context.Background(), api, "/ok", []APIArgument{})
+	if err == nil || err.Error() != "unavailable" {
+		t.Errorf("Expected %s, got %v", "unavailable", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected %d calls, got %d", 2, calls)
+	}
+
+	// No retries by default
+	atomic.StoreInt32(&calls, 0)
+	api.retry = RetryPolicy{}
+	_, err = get[response](context.Background(), api, "/ok", []APIArgument{})
+	if err == nil || err.Error() != "rate limit exceeded" {
+		t.Errorf("Expected %s, got %v", "rate limit exceeded", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected %d calls, got %d", 1, calls)
+	}
+}
+
+func TestGetRetriesStopOnContext(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", "20")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	api := WhaleAlertAPI{client: &http.Client{}, url: server.URL, key: "OK", retry: DefaultRetryPolicy()}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := get[response](ctx, api, "/ok", []APIArgument{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected %s, got %v", context.DeadlineExceeded, err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected %d calls, got %d", 1, calls)
+	}
+}
+
+func TestGetRetryAfterTooLong(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"result":"error","message":"rate limit exceeded"}`))
+	}))
+	defer server.Close()
+
+	// Server asks to wait longer than MaxBackoff, so 429 is returned without waiting
+	api := WhaleAlertAPI{client: &http.Client{}, url: server.URL, key: "OK", retry: DefaultRetryPolicy()}
+	started := time.Now()
+	_, err := get[response](context.Background(), api, "/ok", []APIArgument{})
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Expected %s, got %v", ErrRateLimited, err)
+	}
+	if calls != 1 || time.Since(started) > time.Second {
+		t.Errorf("Expected %d call without waiting, got %d in %s", 1, calls, time.Since(started))
+	}
+}
+
+func TestIsTransientError(t *testing.T) {
+	if IsTransientError(nil) {
+		t.Errorf("Expected nil not to be transient")
+	}
+	if IsTransientError(context.Canceled) {
+		t.Errorf("Expected context.Canceled not to be transient")
+	}
+	transient := []error{
+		&url.Error{Op: "Get", URL: "http://x", Err: &net.OpError{Op: "read", Err: syscall.ECONNRESET}},
+		&url.Error{Op: "Get", URL: "http://x", Err: &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}},
+		&url.Error{Op: "Get", URL: "http://x", Err: io.ErrUnexpectedEOF},
+		&net.DNSError{Err: "timeout", IsTimeout: true},
+	}
+	for _, err := range transient {
+		if !IsTransientError(err) {
+			t.Errorf("Expected %s to be transient", err)
+		}
+	}
+	permanent := []error{
+		errors.New("no recorded interaction"),
+		&url.Error{Op: "Get", URL: "http://x", Err: errors.New("unsupported protocol scheme")},
+		&url.Error{Op: "Get", URL: "http://x", Err: &tls.CertificateVerificationError{Err: x509.UnknownAuthorityError{}}},
+		&url.Error{Op: "Get", URL: "http://x", Err: context.Canceled},
+	}
+	for _, err := range permanent {
+		if IsTransientError(err) {
+			t.Errorf("Expected %s not to be transient", err)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package whalealertapi
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultLookback is how far in the past /transactions can start with the free plan
+const DefaultLookback = time.Hour
+
+var (
+	ErrMissingStart  error = errors.New("start is missing")
+	ErrStartAfterEnd error = errors.New("start must be before end")
+	ErrTimeInFuture  error = errors.New("time is in the future")
+	ErrStartTooOld   error = errors.New("start is older than allowed lookback")
+	ErrWindowTooLong error = errors.New("time window is longer than allowed")
+)
+
+// TimeRangeError is returned when time range of a request is invalid
+// It unwraps to one of ErrMissingStart, ErrStartAfterEnd, ErrTimeInFuture, ErrStartTooOld, ErrWindowTooLong
+type TimeRangeError struct {
+	Start time.Time
+	End   time.Time
+	Err   error
+	// field is request field which is invalid, plan is plan whose limits were checked
+	field string
+	plan  Plan
+}
+
+func (e *TimeRangeError) Error() string {
+	format := time.RFC3339
+	if e.End.IsZero() {
+		return fmt.Sprintf("invalid time range from %s: %s", e.Start.Format(format), e.Err)
+	}
+	return fmt.Sprintf("invalid time range %s - %s: %s", e.Start.Format(format), e.End.Format(format), e.Err)
+}
+
+func (e *TimeRangeError) Unwrap() error {
+	return e.Err
+}
+
+// Time returns Timestamp as time.Time
+func (t Transaction) Time() time.Time {
+	return time.Unix(int64(t.Timestamp), 0)
+}
+
+// StartTime returns Start as time.Time, it is zero time when Start is not set
+func (t TransactionsRequest) StartTime() time.Time {
+	return unixTime(t.Start)
+}
+
+// EndTime returns End as time.Time, it is zero time when End is not set
+func (t TransactionsRequest) EndTime() time.Time {
+	return unixTime(t.End)
+}
+
+// TransactionsRequestBuilder builds TransactionsRequest from time.Time and time.Duration
+// and validates time range before any request is sent
+//
+//	req, err := NewTransactionsRequest().WithLast(30 * time.Minute).WithMinValue(1000000).Build()
+//	res, err := api.Transactions(req.Start, req)
+type TransactionsRequestBuilder struct {
+	request TransactionsRequest
+	start   time.Time
+	end     time.Time
+	last    time.Duration
+	plan    Plan
+	now     func() time.Time
+}
+
+// NewTransactionsRequest returns builder checking DefaultLookback and window of DefaultPlan
+func NewTransactionsRequest() *TransactionsRequestBuilder {
+	return NewTransactionsRequestFor(DefaultPlan).WithLookback(DefaultLookback)
+}
+
+// NewTransactionsRequestFor returns builder checking time range against lookback and window of given plan
+func NewTransactionsRequestFor(plan Plan) *TransactionsRequestBuilder {
+	return &TransactionsRequestBuilder{
+		plan: plan,
+		now:  time.Now,
+	}
+}
+
+// WithPlan sets plan which lookback and window limits are checked
+func (b *TransactionsRequestBuilder) WithPlan(plan Plan) *TransactionsRequestBuilder {
+	b.plan = plan
+	return b
+}
+
+// WithTimeRange sets start (exclusive) and end (inclusive) of the request
+func (b *TransactionsRequestBuilder) WithTimeRange(start, end time.Time) *TransactionsRequestBuilder {
+	b.start = start
+	b.end = end
+	b.last = 0
+	return b
+}
+
+// WithStart sets start (exclusive) of the request, transactions are returned up to now
+func (b *TransactionsRequestBuilder) WithStart(start time.Time) *TransactionsRequestBuilder {
+	b.start = start
+	b.end = time.Time{}
+	b.last = 0
+	return b
+}
+
+// WithLast sets request to return transactions from given period before Build is called
+func (b *TransactionsRequestBuilder) WithLast(d time.Duration) *TransactionsRequestBuilder {
+	b.start = time.Time{}
+	b.end = time.Time{}
+	b.last = d
+	return b
+}
+
+// WithLookback sets how far in the past request can start, zero disables the check
+// It overrides lookback of the plan
+func (b *TransactionsRequestBuilder) WithLookback(lookback time.Duration) *TransactionsRequestBuilder {
+	b.plan.MaxLookback = lookback
+	return b
+}
+
+// WithMaxWindow sets longest allowed time between start and end, zero disables the check
+// It overrides window of the plan
+func (b *TransactionsRequestBuilder) WithMaxWindow(window time.Duration) *TransactionsRequestBuilder {
+	b.plan.MaxWindow = window
+	return b
+}
+
+func (b *TransactionsRequestBuilder) WithMinValue(minValue uint) *TransactionsRequestBuilder {
+	b.request.MinValue = minValue
+	return b
+}
+
+func (b *TransactionsRequestBuilder) WithLimit(limit uint) *TransactionsRequestBuilder {
+	b.request.Limit = limit
+	return b
+}
+
+func (b *TransactionsRequestBuilder) WithCurrency(currency string) *TransactionsRequestBuilder {
+	b.request.Currency = currency
+	return b
+}
+
+func (b *TransactionsRequestBuilder) WithCursor(cursor string) *TransactionsRequestBuilder {
+	b.request.Cursor = cursor
+	return b
+}
+
+// Build validates time range and returns request, errors are *TimeRangeError
+func (b *TransactionsRequestBuilder) Build() (TransactionsRequest, error) {
+	now := b.now()
+	start, end := b.start, b.end
+	if b.last > 0 {
+		start = now.Add(-b.last)
+	}
+	if err := validateTimeRange(start, end, now, b.plan); err != nil {
+		return TransactionsRequest{}, err
+	}
+	request := b.request
+	request.Start = uint(start.Unix())
+	if !end.IsZero() {
+		request.End = uint(end.Unix())
+	}
+	return request, nil
+}
+
+// validateTimeRange returns first problem of time range, see timeRangeProblems
+func validateTimeRange(start, end, now time.Time, plan Plan) error {
+	if problems := timeRangeProblems(start, end, now, plan); len(problems) > 0 {
+		return problems[0]
+	}
+	return nil
+}
+
+// timeRangeProblems checks that start is before end, none of them is in the future,
+// start fits lookback of the plan and the window is not longer than the plan allows, zero end means now
+// It is the only check of time range, used by both TransactionsRequestBuilder and TransactionsRequest.Validate
+func timeRangeProblems(start, end, now time.Time, plan Plan) []*TimeRangeError {
+	problems := []*TimeRangeError{}
+	add := func(field string, err error) {
+		problems = append(problems, &TimeRangeError{Start: start, End: end, Err: err, field: field, plan: plan})
+	}
+	missingStart := start.IsZero() || start.Unix() <= 0
+	if missingStart {
+		add("start", ErrMissingStart)
+	} else {
+		if start.After(now) {
+			add("start", ErrTimeInFuture)
+		}
+		if plan.MaxLookback > 0 && now.Sub(start) > plan.MaxLookback {
+			add("start", ErrStartTooOld)
+		}
+	}
+	if !end.IsZero() {
+		if end.After(now) {
+			add("end", ErrTimeInFuture)
+		}
+		if !missingStart && !start.Before(end) {
+			add("end", ErrStartAfterEnd)
+		}
+		if !missingStart && plan.MaxWindow > 0 && end.Sub(start) > plan.MaxWindow {
+			add("end", ErrWindowTooLong)
+		}
+	}
+	return problems
+}
+
+// fieldError describes the problem as invalid field of the request
+func (e *TimeRangeError) fieldError() FieldError {
+	message := e.Err.Error()
+	switch e.Err {
+	case ErrMissingStart:
+		message = "is required"
+	case ErrTimeInFuture:
+		message = "is in the future"
+	case ErrStartAfterEnd:
+		message = "must be after start"
+	case ErrStartTooOld:
+		message = fmt.Sprintf("is older than %s allowed by %s plan", e.plan.MaxLookback, e.plan.Name)
+	case ErrWindowTooLong:
+		message = fmt.Sprintf("window is longer than %s allowed by %s plan", e.plan.MaxWindow, e.plan.Name)
+	}
+	return FieldError{Field: e.field, Message: message, Err: e}
+}
+
+// unixTime converts unix timestamp to time.Time, 0 gives zero time
+func unixTime(timestamp uint) time.Time {
+	if timestamp == 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(timestamp), 0)
+}
@@ -0,0 +1,83 @@
+package whalealertapi
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTransactionsRequestBuilder(t *testing.T) {
+	now := time.Unix(1679774558, 0)
+	builderFor := func(plan Plan) *TransactionsRequestBuilder {
+		b := NewTransactionsRequestFor(plan)
+		b.now = func() time.Time { return now }
+		return b
+	}
+	builder := func() *TransactionsRequestBuilder {
+		b := NewTransactionsRequest()
+		b.now = func() time.Time { return now }
+		return b
+	}
+
+	req, err := builder().WithLast(30 * time.Minute).WithMinValue(500000).WithLimit(10).WithCurrency("usdt").Build()
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err)
+	}
+	expected := TransactionsRequest{Start: 1679772758, MinValue: 500000, Limit: 10, Currency: "usdt"}
+	if req != expected {
+		t.Errorf("Expected %+v, got %+v", expected, req)
+	}
+	if !req.StartTime().Equal(now.Add(-30*time.Minute)) || !req.EndTime().IsZero() {
+		t.Errorf("Unexpected start and end time %s, %s", req.StartTime(), req.EndTime())
+	}
+
+	req, err = builder().WithTimeRange(now.Add(-time.Hour), now.Add(-time.Minute)).Build()
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err)
+	}
+	if req.Start != 1679770958 || req.End != 1679774498 {
+		t.Errorf("Expected range %d - %d, got %d - %d", 1679770958, 1679774498, req.Start, req.End)
+	}
+
+	tests := []struct {
+		builder  *TransactionsRequestBuilder
+		expected error
+	}{
+		{builder(), ErrMissingStart},
+		{builder().WithStart(now.Add(time.Minute)), ErrTimeInFuture},
+		{builder().WithTimeRange(now.Add(-time.Minute), now.Add(time.Minute)), ErrTimeInFuture},
+		{builder().WithTimeRange(now.Add(-time.Minute), now.Add(-2*time.Minute)), ErrStartAfterEnd},
+		{builder().WithTimeRange(now.Add(-time.Minute), now.Add(-time.Minute)), ErrStartAfterEnd},
+		{builder().WithLast(2 * time.Hour), ErrStartTooOld},
+		{builderFor(PlanFree).WithLast(2 * time.Hour), ErrStartTooOld},
+		{builderFor(PlanPersonal).WithPlan(PlanFree).WithLast(2 * time.Hour), ErrStartTooOld},
+		{builder().WithLookback(24*time.Hour).WithTimeRange(now.Add(-3*time.Hour), now), ErrWindowTooLong},
+	}
+	for i, test := range tests {
+		_, err := test.builder.Build()
+		if !errors.Is(err, test.expected) {
+			t.Errorf("%d: expected %s, got %v", i, test.expected, err)
+		}
+		var rangeErr *TimeRangeError
+		if !errors.As(err, &rangeErr) {
+			t.Errorf("%d: expected *TimeRangeError, got %T", i, err)
+		}
+	}
+
+	// Lookback depends on plan
+	if _, err := builderFor(PlanPersonal).WithLast(2 * time.Hour).Build(); err != nil {
+		t.Errorf("Expected nil, got %s", err)
+	}
+
+	_, err = builder().WithLookback(0).WithMaxWindow(0).WithTimeRange(now.Add(-48*time.Hour), now).Build()
+	if err != nil {
+		t.Errorf("Expected disabled checks to pass, got %s", err)
+	}
+}
+
+func TestTransactionTime(t *testing.T) {
+	tx := Transaction{Timestamp: 1679774519}
+	if !tx.Time().Equal(time.Date(2023, 3, 25, 20, 1, 59, 0, time.UTC)) {
+		t.Errorf("Unexpected time %s", tx.Time().UTC())
+	}
+}
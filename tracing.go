@@ -0,0 +1,110 @@
+package whalealertapi
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// SpanContext identifies trace span, it is propagated with W3C traceparent header
+type SpanContext struct {
+	TraceID [16]byte
+	SpanID  [8]byte
+	Sampled bool
+}
+
+// NewSpanContext returns sampled span starting a new trace
+func NewSpanContext() SpanContext {
+	var span SpanContext
+	rand.Read(span.TraceID[:])
+	rand.Read(span.SpanID[:])
+	span.Sampled = true
+	return span
+}
+
+// ParseTraceParent parses value of traceparent header, like "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+func ParseTraceParent(traceparent string) (SpanContext, error) {
+	var span SpanContext
+	parts := strings.Split(strings.TrimSpace(traceparent), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return span, fmt.Errorf("invalid traceparent %q", traceparent)
+	}
+	version, err := hex.DecodeString(parts[0])
+	if err != nil || len(version) != 1 {
+		return span, fmt.Errorf("invalid traceparent version %q", parts[0])
+	}
+	if len(parts[1]) != 32 || !decodeHex(span.TraceID[:], parts[1]) {
+		return span, fmt.Errorf("invalid trace id %q", parts[1])
+	}
+	if len(parts[2]) != 16 || !decodeHex(span.SpanID[:], parts[2]) {
+		return span, fmt.Errorf("invalid span id %q", parts[2])
+	}
+	var flags [1]byte
+	if len(parts[3]) != 2 || !decodeHex(flags[:], parts[3]) {
+		return span, fmt.Errorf("invalid trace flags %q", parts[3])
+	}
+	span.Sampled = flags[0]&1 == 1
+	if !span.IsValid() {
+		return span, fmt.Errorf("invalid traceparent %q", traceparent)
+	}
+	return span, nil
+}
+
+// decodeHex decodes lower case hex s to dst
+func decodeHex(dst []byte, s string) bool {
+	if strings.ToLower(s) != s {
+		return false
+	}
+	_, err := hex.Decode(dst, []byte(s))
+	return err == nil
+}
+
+// IsValid returns true when neither trace nor span ID is zero
+func (s SpanContext) IsValid() bool {
+	return s.TraceID != [16]byte{} && s.SpanID != [8]byte{}
+}
+
+// TraceIDString returns trace ID as hex string
+func (s SpanContext) TraceIDString() string {
+	return hex.EncodeToString(s.TraceID[:])
+}
+
+// SpanIDString returns span ID as hex string
+func (s SpanContext) SpanIDString() string {
+	return hex.EncodeToString(s.SpanID[:])
+}
+
+// TraceParent returns value of traceparent header for the span
+func (s SpanContext) TraceParent() string {
+	flags := "00"
+	if s.Sampled {
+		flags = "01"
+	}
+	return "00-" + s.TraceIDString() + "-" + s.SpanIDString() + "-" + flags
+}
+
+// child returns new span of the same trace
+func (s SpanContext) child() SpanContext {
+	child := s
+	rand.Read(child.SpanID[:])
+	return child
+}
+
+type spanContextKey struct{}
+
+// ContextWithSpan returns context with span, requests sent with the context are its children
+// Invalid span is ignored
+func ContextWithSpan(ctx context.Context, span SpanContext) context.Context {
+	if !span.IsValid() {
+		return ctx
+	}
+	return context.WithValue(ctx, spanContextKey{}, span)
+}
+
+// SpanFromContext returns span set by ContextWithSpan
+func SpanFromContext(ctx context.Context) (SpanContext, bool) {
+	span, ok := ctx.Value(spanContextKey{}).(SpanContext)
+	return span, ok
+}
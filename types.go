@@ -2,6 +2,8 @@ package whalealertapi
 
 import (
 	"fmt"
+	"net/http"
+	"time"
 )
 
 // Documentation can be found here:
@@ -9,31 +11,31 @@ import (
 
 // Blockchain structure keeps info about blockchain name, available symbols, and blockchain status
 type Blockchain struct {
-	Name    string   `json:"name"`
-	Symbols []string `json:"symbols"`
-	Status  string   `json:"status"`
+	Name    BlockchainName   `json:"name"`
+	Symbols []string         `json:"symbols"`
+	Status  BlockchainStatus `json:"status"`
 }
 
 // Owner keeps data about wallet address owner
 type Owner struct {
-	Address   string `json:"address"`
-	Owner     string `json:"owner"`
-	OwnerType string `json:"owner_type"`
+	Address   string    `json:"address"`
+	Owner     string    `json:"owner"`
+	OwnerType OwnerType `json:"owner_type"`
 }
 
 // Transaction keeps data about transaction which occured
 type Transaction struct {
-	Blockchain       string  `json:"blockchain"`
-	Symbol           string  `json:"symbol"`
-	TransactionType  string  `json:"transaction_type"`
-	Hash             string  `json:"hash"`
-	From             Owner   `json:"from"`
-	To               Owner   `json:"to"`
-	Timestamp        uint    `json:"timestamp"`
-	Amount           float64 `json:"amount"`
-	AmountUSD        float64 `json:"amount_usd"`
-	TransactionCount uint    `json:"transaction_count"`
-	ID               string  `json:"id"`
+	Blockchain       BlockchainName  `json:"blockchain"`
+	Symbol           string          `json:"symbol"`
+	TransactionType  TransactionType `json:"transaction_type"`
+	Hash             string          `json:"hash"`
+	From             Owner           `json:"from"`
+	To               Owner           `json:"to"`
+	Timestamp        uint            `json:"timestamp"`
+	Amount           Decimal         `json:"amount"`
+	AmountUSD        Decimal         `json:"amount_usd"`
+	TransactionCount uint            `json:"transaction_count"`
+	ID               string          `json:"id"`
 }
 
 // TransactionResponse is returned when /transactions endpoint returns 200
@@ -66,7 +68,51 @@ type ErrorResponse struct {
 }
 
 func (e ErrorResponse) Error() string {
-	return fmt.Sprintf(e.Message)
+	return e.Message
+}
+
+// Unwrap returns *APIError with details about failed call
+func (e ErrorResponse) Unwrap() error {
+	return e.Err
+}
+
+// APIError keeps details about failed API call
+// It unwraps to one of sentinel errors (ErrUnauthorized, ErrRateLimited, ErrServerError, ErrInvalidParameter, ErrNotFound)
+type APIError struct {
+	StatusCode int
+	Endpoint   string
+	// URL of the request, access key is redacted
+	URL string
+	// Body is beginning of raw response body
+	Body    string
+	Message string
+	Err     error
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%s returned %d", e.Endpoint, e.StatusCode)
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// ResponseMeta describes HTTP response of API call
+type ResponseMeta struct {
+	StatusCode int
+	Header     http.Header
+	// Duration is time of the whole call, including retries and waiting for rate limiter
+	Duration time.Duration
+	// Attempts is number of sent requests
+	Attempts int
+	// URL is final url of the request, access key is redacted
+	URL string
+	// Cached is true when response was taken from cache, no request was sent then
+	Cached bool
 }
 
 type TransactionsRequest struct {
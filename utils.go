@@ -1,12 +1,17 @@
 package whalealertapi
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"reflect"
 	"strings"
+	"time"
 )
 
 var (
@@ -14,27 +19,51 @@ var (
 	ErrMissingAccessKey error = errors.New("access key is missing")
 	ErrIncorrectJSON    error = errors.New("incorrect JSON response")
 	ErrNotFound         error = errors.New("endpoint not found")
+	ErrUnauthorized     error = errors.New("unauthorized")
+	ErrRateLimited      error = errors.New("rate limited")
+	ErrServerError      error = errors.New("server error")
+	ErrInvalidParameter error = errors.New("invalid parameter")
 )
 
+// errorBodySnippetSize is number of response body bytes kept in APIError
+const errorBodySnippetSize = 512
+
 // Single argument that can be passed to api endpoint
 type APIArgument struct {
 	Key   string `json:"name"`
 	Value string `json:"value"`
 }
 
-// Converts Key and Value to url format key=value
-func (arg APIArgument) toURLArgument() string {
-	return fmt.Sprintf("%s=%v", arg.Key, arg.Value)
+// buildURL joins base url with endpoint and encodes arguments as query string
+// Endpoint path segments must be already escaped, see endpointPath
+// Arguments are encoded sorted by key, so the same arguments always give the same url
+func buildURL(base, endpoint string, args []APIArgument) (string, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	escaped := strings.TrimRight(u.EscapedPath(), "/") + "/" + strings.TrimLeft(endpoint, "/")
+	unescaped, err := url.PathUnescape(escaped)
+	if err != nil {
+		return "", err
+	}
+	u.Path = unescaped
+	u.RawPath = escaped
+	query := u.Query()
+	for _, arg := range args {
+		query.Add(arg.Key, arg.Value)
+	}
+	u.RawQuery = query.Encode()
+	return u.String(), nil
 }
 
-// Converts list of APIArgument to url format key1=value1&key2=value2
-func toURLArguments(args []APIArgument) string {
-	urlArgList := []string{}
-	for _, arg := range args {
-		urlArgList = append(urlArgList, arg.toURLArgument())
+// endpointPath builds endpoint from path segments, every segment is escaped
+func endpointPath(segments ...string) string {
+	escaped := make([]string, len(segments))
+	for i, segment := range segments {
+		escaped[i] = url.PathEscape(segment)
 	}
-	result := strings.Join(urlArgList, "&")
-	return result
+	return "/" + strings.Join(escaped, "/")
 }
 
 func isStructEmpty[T any](t T) bool {
@@ -48,46 +77,235 @@ func isStructEmpty[T any](t T) bool {
 }
 
 // get is doing get requests to specified url
-// It returns T or error
-func get[T any](client *http.Client, url string, key string, endpoint string, args []APIArgument) (*T, error) {
-	err := checkRequiredFields(url, key)
+// It returns T or error, see getWithMeta
+func get[T any](ctx context.Context, api WhaleAlertAPI, endpoint string, args []APIArgument) (*T, error) {
+	result, _, err := getWithMeta[T](ctx, api, endpoint, args)
+	return result, err
+}
+
+// getWithMeta is doing get requests to specified url, see doWithMeta
+func getWithMeta[T any](ctx context.Context, api WhaleAlertAPI, endpoint string, args []APIArgument) (*T, *ResponseMeta, error) {
+	return doWithMeta[T](ctx, api, http.MethodGet, endpoint, args)
+}
+
+// doWithMeta is doing requests with given method to specified url, args are sent in query
+// Responses are taken from api cache when possible, identical concurrent GET and HEAD requests are coalesced when enabled
+// It returns T or error, and metadata of last response, which is nil when no response was received
+// Result is nil for HEAD requests and 204 responses
+func doWithMeta[T any](ctx context.Context, api WhaleAlertAPI, method, endpoint string, args []APIArgument) (*T, *ResponseMeta, error) {
+	err := checkRequiredFields(api.url, api.key)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/%s?%s", url, endpoint, toURLArguments(args)), nil)
+	requestURL, err := buildURL(api.url, endpoint, args)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	cacheKey, cacheTTL := method+" "+requestURL, api.cacheTTL(method, endpoint, args)
+	if cacheTTL > 0 {
+		if body, ok := api.cache.get(cacheKey); ok {
+			if result, err := decodeBody[T](body); err == nil {
+				u, _ := url.Parse(requestURL)
+				return result, &ResponseMeta{StatusCode: http.StatusOK, URL: redactURL(u, api.key), Cached: true}, nil
+			}
+		}
+	}
+	var body []byte
+	var meta *ResponseMeta
+	if api.flights != nil && idempotent(method) {
+		body, meta, err = api.flights.do(ctx, cacheKey+" "+api.key, func(ctx context.Context) ([]byte, *ResponseMeta, error) {
+			return fetch(ctx, api, method, endpoint, requestURL)
+		})
+	} else {
+		body, meta, err = fetch(ctx, api, method, endpoint, requestURL)
 	}
-	req.Header.Add("X-WA-API-KEY", key)
-	response, err := client.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, meta, err
 	}
-	defer response.Body.Close()
+	// HEAD and 204 responses have no body, only metadata is returned
+	if method == http.MethodHead || meta.StatusCode == http.StatusNoContent {
+		return nil, meta, nil
+	}
+	result, err := decodeBody[T](body)
+	if err == nil && cacheTTL > 0 && cacheableBody(endpoint, body) {
+		api.cache.set(cacheKey, body, cacheTTL)
+	}
+	return result, meta, err
+}
 
-	if response.StatusCode == 200 {
-		var result *T
-		err = json.NewDecoder(response.Body).Decode(&result)
+// fetch sends request and returns body of successful response
+// Request is cancelled when ctx is done, failed attempts are repeated according to api retry policy
+// Only GET and HEAD requests are repeated
+// Every attempt waits for api rate limiter
+func fetch(ctx context.Context, api WhaleAlertAPI, method, endpoint, requestURL string) ([]byte, *ResponseMeta, error) {
+	retry := api.retry
+	if !idempotent(method) {
+		retry = RetryPolicy{}
+	}
+	var meta *ResponseMeta
+	started := time.Now()
+	ctx = withCallID(ctx)
+	for attempt := 1; ; attempt++ {
+		if err := api.waitLimiter(ctx, endpoint); err != nil {
+			return nil, meta, err
+		}
+		attemptCtx, info := api.startAttempt(ctx, method, endpoint, requestURL, attempt)
+		response, err := doRequest(attemptCtx, api, method, requestURL)
 		if err != nil {
-			return nil, err
+			api.finishAttempt(attemptCtx, info, 0, 0, err)
+			if !retry.canRetry(attempt) || !retry.retryError(err) {
+				return nil, meta, err
+			}
+			delay := retry.backoff(attempt)
+			api.retrying(attemptCtx, info, delay, "error", err)
+			if err := sleepContext(ctx, delay); err != nil {
+				return nil, meta, err
+			}
+			continue
+		}
+		meta = &ResponseMeta{
+			StatusCode: response.StatusCode,
+			Header:     response.Header,
+			Attempts:   attempt,
+			URL:        redactURL(response.Request.URL, api.key),
 		}
-		if isStructEmpty(*result) {
-			return nil, ErrIncorrectJSON
+		api.observeQuota(response.Header)
+		counter := &countingReader{r: response.Body}
+		response.Body = struct {
+			io.Reader
+			io.Closer
+		}{counter, response.Body}
+		if delay, ok := retry.delay(attempt, response.Header); ok && retry.canRetry(attempt) && retry.retryStatus(response.StatusCode) {
+			discardBody(response)
+			api.finishAttempt(attemptCtx, info, response.StatusCode, counter.n, nil)
+			api.retrying(attemptCtx, info, delay, "status", response.StatusCode)
+			if err := sleepContext(ctx, delay); err != nil {
+				meta.Duration = time.Since(started)
+				return nil, meta, err
+			}
+			continue
 		}
-		return result, nil
+		body, err := readResponse(response, endpoint, api.key)
+		meta.Duration = time.Since(started)
+		api.finishAttempt(attemptCtx, info, response.StatusCode, counter.n, err)
+		return body, meta, err
+	}
+}
+
+// idempotent returns true for methods which may be sent repeatedly, only their requests are retried and coalesced
+func idempotent(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
+}
+
+// doRequest sends single request with access key
+func doRequest(ctx context.Context, api WhaleAlertAPI, method, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("X-WA-API-KEY", api.key)
+	if span, ok := SpanFromContext(ctx); ok {
+		req.Header.Set("traceparent", span.TraceParent())
+	}
+	if api.userAgent != "" {
+		req.Header.Set("User-Agent", api.userAgent)
+	}
+	return api.doer()(req)
+}
+
+// readResponse returns body of successful response or decodes it to ErrorResponse, body is always closed
+// ErrorResponse wraps *APIError which describes the failure, 404 gives ErrNotFound
+func readResponse(response *http.Response, endpoint, key string) ([]byte, error) {
+	defer response.Body.Close()
 
+	if response.StatusCode == 200 || response.StatusCode == 204 {
+		return io.ReadAll(response.Body)
 	}
+	// ErrNotFound is returned as is, so it can still be compared with ==
 	if response.StatusCode == 404 {
 		return nil, ErrNotFound
 	}
-	var errResult *ErrorResponse
-	err = json.NewDecoder(response.Body).Decode(&errResult)
+	body, err := io.ReadAll(io.LimitReader(response.Body, 1<<20))
 	if err != nil {
 		return nil, err
 	}
+	apiErr := &APIError{
+		StatusCode: response.StatusCode,
+		Endpoint:   endpoint,
+		URL:        redactURL(response.Request.URL, key),
+		Body:       snippet(body, errorBodySnippetSize),
+		Err:        statusError(response.StatusCode),
+	}
+	var errResult *ErrorResponse
+	err = json.NewDecoder(bytes.NewReader(body)).Decode(&errResult)
+	if err != nil {
+		errResult = &ErrorResponse{Message: err.Error(), Result: "error"}
+	}
+	if errResult == nil {
+		errResult = &ErrorResponse{Message: http.StatusText(response.StatusCode), Result: "error"}
+	}
+	apiErr.Message = errResult.Message
+	errResult.Err = apiErr
 	return nil, errResult
 }
 
+// decodeBody decodes body of successful response to T
+func decodeBody[T any](body []byte) (*T, error) {
+	var result *T
+	err := json.NewDecoder(bytes.NewReader(body)).Decode(&result)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil || isStructEmpty(*result) {
+		return nil, ErrIncorrectJSON
+	}
+	return result, nil
+}
+
+// statusError maps HTTP status code to sentinel error
+func statusError(code int) error {
+	switch {
+	case code == http.StatusBadRequest:
+		return ErrInvalidParameter
+	case code == http.StatusUnauthorized || code == http.StatusForbidden:
+		return ErrUnauthorized
+	case code == http.StatusNotFound:
+		return ErrNotFound
+	case code == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case code >= 500:
+		return ErrServerError
+	}
+	return nil
+}
+
+// redactURL returns url as string with access key removed
+func redactURL(u *url.URL, key string) string {
+	if u == nil {
+		return ""
+	}
+	redacted := *u
+	redacted.User = nil
+	query := redacted.Query()
+	if query.Has("api_key") {
+		query.Set("api_key", "REDACTED")
+		redacted.RawQuery = query.Encode()
+	}
+	result := redacted.String()
+	if key != "" {
+		result = strings.ReplaceAll(result, key, "REDACTED")
+	}
+	return result
+}
+
+// snippet returns at most n first bytes of body as string
+func snippet(body []byte, n int) string {
+	if len(body) > n {
+		return string(body[:n])
+	}
+	return string(body)
+}
+
 // checkRequiredFields returns error when url or key are empty
 func checkRequiredFields(url, key string) error {
 	if url == "" {
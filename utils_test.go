@@ -1,6 +1,8 @@
 package whalealertapi
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"sort"
@@ -8,30 +10,63 @@ import (
 	"testing"
 )
 
-func TestToURLArguments(t *testing.T) {
-	args := []APIArgument{
+func TestBuildURL(t *testing.T) {
+	tests := []struct {
+		base     string
+		endpoint string
+		args     []APIArgument
+		expected string
+	}{
+		{"https://api.whale-alert.io/v1", "/status", nil, "https://api.whale-alert.io/v1/status"},
+		{"https://api.whale-alert.io/v1/", "/status", nil, "https://api.whale-alert.io/v1/status"},
+		{"https://api.whale-alert.io/v1//", "status", nil, "https://api.whale-alert.io/v1/status"},
+		{"https://api.whale-alert.io", "/status", nil, "https://api.whale-alert.io/status"},
+		{"https://api.whale-alert.io/", "/status", nil, "https://api.whale-alert.io/status"},
+		{"http://localhost:8080/proxy/whale/v1", "/transactions", nil, "http://localhost:8080/proxy/whale/v1/transactions"},
 		{
-			Key:   "testKey1",
-			Value: "testValue1",
+			"https://api.whale-alert.io/v1",
+			endpointPath("transaction", "ethereum", "0xab/cd?e"),
+			nil,
+			"https://api.whale-alert.io/v1/transaction/ethereum/0xab%2Fcd%3Fe",
 		},
 		{
-			Key:   "testKey1",
-			Value: "testValue1",
+			"https://api.whale-alert.io/v1",
+			"/transactions",
+			[]APIArgument{
+				{Key: "start", Value: "1679760122"},
+				{Key: "cursor", Value: "a&b=c"},
+				{Key: "currency", Value: "usd t"},
+			},
+			"https://api.whale-alert.io/v1/transactions?currency=usd+t&cursor=a%26b%3Dc&start=1679760122",
+		},
+		{
+			"https://api.whale-alert.io/v1?api_key=abc",
+			"/status",
+			[]APIArgument{{Key: "limit", Value: "10"}},
+			"https://api.whale-alert.io/v1/status?api_key=abc&limit=10",
 		},
 	}
-	expected := "testKey1=testValue1&testKey1=testValue1"
-	actual := toURLArguments(args)
-	if actual != expected {
-		t.Errorf("Expected %s, got %s", expected, actual)
+	for _, test := range tests {
+		got, err := buildURL(test.base, test.endpoint, test.args)
+		if err != nil {
+			t.Errorf("Expected nil, got %s", err)
+		}
+		if got != test.expected {
+			t.Errorf("Expected %s, got %s", test.expected, got)
+		}
 	}
 
-	args = []APIArgument{}
-	expected = ""
-	actual = toURLArguments(args)
-	if actual != expected {
-		t.Errorf("Expected %s, got %s", expected, actual)
+	_, err := buildURL("http://[::1", "/status", nil)
+	if err == nil {
+		t.Errorf("Expected error for malformed base url")
 	}
+}
 
+func TestEndpointPath(t *testing.T) {
+	expected := "/transaction/bitcoin/a%20b"
+	if got := endpointPath("transaction", "bitcoin", "a b"); got != expected {
+		t.Errorf("Expected %s, got %s", expected, got)
+	}
 }
 
 type response struct {
@@ -69,7 +104,9 @@ func TestGet(t *testing.T) {
 	defer server.Close()
 
 	client := &http.Client{}
-	res, err := get[response](client, server.URL, "OK", "/ok", []APIArgument{})
+	api := WhaleAlertAPI{client: client, url: server.URL, key: "OK"}
+	apiNotOK := WhaleAlertAPI{client: client, url: server.URL, key: "NOT_OK"}
+	res, err := get[response](context.Background(), api, "/ok", []APIArgument{})
 	if err != nil {
 		t.Errorf("Expected %s, got %s", "nil", err)
 	}
@@ -77,7 +114,7 @@ func TestGet(t *testing.T) {
 		t.Errorf("Expected %s, got %s", "OK!", res.Response)
 	}
 
-	res, err = get[response](client, server.URL, "NOT_OK", "/bad_request_api", []APIArgument{})
+	res, err = get[response](context.Background(), apiNotOK, "/bad_request_api", []APIArgument{})
 	if err == nil {
 		t.Errorf("Expected error")
 	}
@@ -88,7 +125,7 @@ func TestGet(t *testing.T) {
 		t.Errorf("Expected %s, got %s", "Bad Request", err)
 	}
 
-	res, err = get[response](client, server.URL, "NOT_OK", "/malformed_json", []APIArgument{})
+	res, err = get[response](context.Background(), apiNotOK, "/malformed_json", []APIArgument{})
 	if err == nil {
 		t.Errorf("Expected error")
 	}
@@ -98,7 +135,7 @@ func TestGet(t *testing.T) {
 	if err.Error() != "unexpected EOF" {
 		t.Errorf("Expected %s, got %s", "unexpected EOF", err)
 	}
-	res, err = get[response](client, server.URL, "NOT_OK", "/bad_request_mj", []APIArgument{})
+	res, err = get[response](context.Background(), apiNotOK, "/bad_request_mj", []APIArgument{})
 	if err == nil {
 		t.Errorf("Expected error")
 	}
@@ -108,7 +145,7 @@ func TestGet(t *testing.T) {
 	if err.Error() != "unexpected EOF" {
 		t.Errorf("Expected %s, got %s", "unexpected EOF", err)
 	}
-	res, err = get[response](client, server.URL, "NOT_OK", "/wrong_json_format", []APIArgument{})
+	res, err = get[response](context.Background(), apiNotOK, "/wrong_json_format", []APIArgument{})
 	if err == nil {
 		t.Errorf("Expected error")
 	}
@@ -120,7 +157,7 @@ func TestGet(t *testing.T) {
 	}
 
 	// Test required fields
-	res, err = get[response](client, "", "NOT_OK", "/wrong_json_format", []APIArgument{})
+	res, err = get[response](context.Background(), WhaleAlertAPI{client: client, key: "NOT_OK"}, "/wrong_json_format", []APIArgument{})
 	if err == nil {
 		t.Errorf("Expected error")
 	}
@@ -130,7 +167,7 @@ func TestGet(t *testing.T) {
 	if err.Error() != "url is missing" {
 		t.Errorf("Expected %s, got %s", "url is missing", err)
 	}
-	res, err = get[response](client, server.URL, "", "/wrong_json_format", []APIArgument{})
+	res, err = get[response](context.Background(), WhaleAlertAPI{client: client, url: server.URL}, "/wrong_json_format", []APIArgument{})
 	if err == nil {
 		t.Errorf("Expected error")
 	}
@@ -218,3 +255,81 @@ func TestToAPIArguments(t *testing.T) {
 		}
 	}
 }
+
+func TestGetAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/unauthorized":
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"result":"error","message":"invalid api_key"}`))
+		case "/bad_request":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"result":"error","message":"invalid value for hash parameter"}`))
+		case "/rate_limited":
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"result":"error","message":"usage limit reached"}`))
+		case "/server_error":
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte(`<html>bad gateway</html>`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	api := WhaleAlertAPI{client: &http.Client{}, url: server.URL, key: "SECRET"}
+	tests := []struct {
+		endpoint string
+		sentinel error
+		status   int
+		message  string
+	}{
+		{"/unauthorized", ErrUnauthorized, http.StatusUnauthorized, "invalid api_key"},
+		{"/bad_request", ErrInvalidParameter, http.StatusBadRequest, "invalid value for hash parameter"},
+		{"/rate_limited", ErrRateLimited, http.StatusTooManyRequests, "usage limit reached"},
+		{"/server_error", ErrServerError, http.StatusBadGateway, "invalid character '<' looking for beginning of value"},
+	}
+	for _, test := range tests {
+		_, err := get[response](context.Background(), api, test.endpoint, []APIArgument{{Key: "api_key", Value: "SECRET"}})
+		if !errors.Is(err, test.sentinel) {
+			t.Errorf("%s: expected %s, got %v", test.endpoint, test.sentinel, err)
+		}
+		if err.Error() != test.message {
+			t.Errorf("%s: expected %s, got %s", test.endpoint, test.message, err)
+		}
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("%s: expected *APIError in chain", test.endpoint)
+		}
+		if apiErr.StatusCode != test.status {
+			t.Errorf("%s: expected %d, got %d", test.endpoint, test.status, apiErr.StatusCode)
+		}
+		if apiErr.Endpoint != test.endpoint {
+			t.Errorf("Expected %s, got %s", test.endpoint, apiErr.Endpoint)
+		}
+		if strings.Contains(apiErr.URL, "SECRET") || !strings.Contains(apiErr.URL, test.endpoint) {
+			t.Errorf("Expected redacted URL with endpoint, got %s", apiErr.URL)
+		}
+	}
+
+	// 404 keeps returning the sentinel itself
+	if _, err := get[response](context.Background(), api, "/missing", []APIArgument{}); err != ErrNotFound {
+		t.Errorf("Expected %s, got %v", ErrNotFound, err)
+	}
+
+	_, err := get[response](context.Background(), api, "/server_error", []APIArgument{})
+	var apiErr *APIError
+	errors.As(err, &apiErr)
+	if apiErr.Body != "<html>bad gateway</html>" {
+		t.Errorf("Expected %s, got %s", "<html>bad gateway</html>", apiErr.Body)
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	if got := snippet([]byte("abcdef"), 3); got != "abc" {
+		t.Errorf("Expected %s, got %s", "abc", got)
+	}
+	if got := snippet([]byte("ab"), 3); got != "ab" {
+		t.Errorf("Expected %s, got %s", "ab", got)
+	}
+}
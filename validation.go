@@ -0,0 +1,138 @@
+package whalealertapi
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Plan describes limits of Whale Alert subscription plan used to validate requests
+// Zero fields are not checked
+type Plan struct {
+	Name string
+	// MaxLimit is the biggest accepted limit
+	MaxLimit uint
+	// MinValue is the smallest accepted min_value in USD
+	MinValue uint
+	// MaxWindow is the longest accepted time between start and end
+	MaxWindow time.Duration
+	// MaxLookback is how far in the past start can be
+	MaxLookback time.Duration
+	// RequestsPerMinute is number of requests allowed by the plan, see WithRateLimit
+	RequestsPerMinute int
+}
+
+var (
+	// DefaultPlan checks only limits which are the same for every plan
+	DefaultPlan = Plan{
+		Name:      "default",
+		MaxLimit:  DefaultTransactionsLimit,
+		MaxWindow: MaxTransactionsWindow,
+	}
+	PlanFree = Plan{
+		Name:              "free",
+		MaxLimit:          DefaultTransactionsLimit,
+		MinValue:          500000,
+		MaxWindow:         MaxTransactionsWindow,
+		MaxLookback:       DefaultLookback,
+		RequestsPerMinute: 10,
+	}
+	PlanPersonal = Plan{
+		Name:              "personal",
+		MaxLimit:          DefaultTransactionsLimit,
+		MinValue:          100000,
+		MaxWindow:         MaxTransactionsWindow,
+		MaxLookback:       30 * 24 * time.Hour,
+		RequestsPerMinute: 60,
+	}
+	PlanProfessional = Plan{
+		Name:              "professional",
+		MaxLimit:          DefaultTransactionsLimit,
+		MinValue:          10000,
+		MaxWindow:         MaxTransactionsWindow,
+		MaxLookback:       30 * 24 * time.Hour,
+		RequestsPerMinute: 1000,
+	}
+)
+
+var currencyRegexp = regexp.MustCompile(`^[a-z0-9]+$`)
+
+// FieldError describes single invalid field of a request
+type FieldError struct {
+	// Field is name of query parameter, for example "min_value"
+	Field   string
+	Message string
+	// Err is sentinel error describing the problem, it can be nil
+	Err error
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError lists every invalid field of a request
+// It matches ErrInvalidParameter and every sentinel of its fields with errors.Is
+type ValidationError struct {
+	Problems []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	problems := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		problems[i] = p.Error()
+	}
+	return "invalid request: " + strings.Join(problems, "; ")
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrInvalidParameter
+}
+
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, len(e.Problems))
+	for i, p := range e.Problems {
+		errs[i] = p
+	}
+	return errs
+}
+
+// Validate checks request against DefaultPlan
+func (t TransactionsRequest) Validate() error {
+	return t.ValidateFor(DefaultPlan)
+}
+
+// ValidateFor checks request against limits of given plan, it returns *ValidationError
+func (t TransactionsRequest) ValidateFor(plan Plan) error {
+	return t.validate(plan, time.Now())
+}
+
+func (t TransactionsRequest) validate(plan Plan, now time.Time) error {
+	problems := []FieldError{}
+	add := func(field, message string, err error) {
+		problems = append(problems, FieldError{Field: field, Message: message, Err: err})
+	}
+	for _, rangeErr := range timeRangeProblems(t.StartTime(), t.EndTime(), now, plan) {
+		problems = append(problems, rangeErr.fieldError())
+	}
+	if plan.MaxLimit > 0 && t.Limit > plan.MaxLimit {
+		add("limit", fmt.Sprintf("must be at most %d", plan.MaxLimit), nil)
+	}
+	if plan.MinValue > 0 && t.MinValue != 0 && t.MinValue < plan.MinValue {
+		add("min_value", fmt.Sprintf("must be at least %d with %s plan", plan.MinValue, plan.Name), nil)
+	}
+	if t.Currency != "" && !currencyRegexp.MatchString(t.Currency) {
+		add("currency", "must be lower case symbol, for example btc", nil)
+	}
+	if t.Cursor != "" && t.Start == 0 {
+		add("cursor", "requires start", nil)
+	}
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package whalealertapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1679774558, 0)
+	valid := TransactionsRequest{Start: 1679774000, End: 1679774500, Limit: 100, MinValue: 500000, Currency: "usdt", Cursor: "a-b-c"}
+	for _, plan := range []Plan{DefaultPlan, PlanFree, PlanPersonal, PlanProfessional} {
+		if err := valid.validate(plan, now); err != nil {
+			t.Errorf("%s: expected nil, got %s", plan.Name, err)
+		}
+	}
+
+	invalid := TransactionsRequest{Start: 1679774000, End: 1679774600, Limit: 101, MinValue: 10000, Currency: "USDT"}
+	err := invalid.validate(PlanFree, now)
+	if !errors.Is(err, ErrInvalidParameter) || !errors.Is(err, ErrTimeInFuture) {
+		t.Errorf("Expected %s and %s, got %v", ErrInvalidParameter, ErrTimeInFuture, err)
+	}
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected *ValidationError, got %T", err)
+	}
+	fields := []string{}
+	for _, p := range validationErr.Problems {
+		fields = append(fields, p.Field)
+	}
+	expected := []string{"end", "limit", "min_value", "currency"}
+	if len(fields) != len(expected) {
+		t.Fatalf("Expected problems with %v, got %v", expected, fields)
+	}
+	for i := range expected {
+		if fields[i] != expected[i] {
+			t.Errorf("Expected problems with %v, got %v", expected, fields)
+		}
+	}
+	// Personal plan allows lower min_value
+	err = TransactionsRequest{Start: 1679774000, MinValue: 100000}.validate(PlanPersonal, now)
+	if err != nil {
+		t.Errorf("Expected nil, got %s", err)
+	}
+
+	tests := []struct {
+		request  TransactionsRequest
+		plan     Plan
+		expected error
+	}{
+		{TransactionsRequest{}, DefaultPlan, ErrMissingStart},
+		{TransactionsRequest{Start: 1679774600}, DefaultPlan, ErrTimeInFuture},
+		{TransactionsRequest{Start: 1679774000, End: 1679773000}, DefaultPlan, ErrStartAfterEnd},
+		{TransactionsRequest{Start: 1679764000, End: 1679774000}, DefaultPlan, ErrWindowTooLong},
+		{TransactionsRequest{Start: 1679764000}, PlanFree, ErrStartTooOld},
+	}
+	for i, test := range tests {
+		if err := test.request.validate(test.plan, now); !errors.Is(err, test.expected) {
+			t.Errorf("%d: expected %s, got %v", i, test.expected, err)
+		}
+	}
+	// Problems of time range are the same as reported by TransactionsRequestBuilder
+	var rangeErr *TimeRangeError
+	if err := (TransactionsRequest{Start: 1679764000}).validate(PlanFree, now); !errors.As(err, &rangeErr) ||
+		err.Error() != "invalid request: start: is older than 1h0m0s allowed by free plan" {
+		t.Errorf("Unexpected error %v", err)
+	}
+	// Every problem of time range is listed
+	err = TransactionsRequest{Start: 1679764000, End: 1679774600}.validate(PlanFree, now)
+	if !errors.Is(err, ErrStartTooOld) || !errors.Is(err, ErrTimeInFuture) || !errors.Is(err, ErrWindowTooLong) {
+		t.Errorf("Expected %s, %s and %s, got %v", ErrStartTooOld, ErrTimeInFuture, ErrWindowTooLong, err)
+	}
+	if !errors.As(err, &validationErr) || len(validationErr.Problems) != 3 {
+		t.Errorf("Expected 3 problems, got %v", err)
+	}
+	// Lookback is not checked without plan
+	if err := (TransactionsRequest{Start: 1579764000}).validate(DefaultPlan, now); err != nil {
+		t.Errorf("Expected nil, got %s", err)
+	}
+	if err := (TransactionsRequest{Cursor: "a-b-c"}).validate(DefaultPlan, now); err == nil || err.Error() != "invalid request: start: is required; cursor: requires start" {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
+
+func TestTransactionsValidation(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(`{"result":"success","cursor":"0-0-0","count":0}`))
+	}))
+	defer server.Close()
+
+	api := New().WithCustomURL(server.URL).WithAccessKey("OK")
+	_, err := api.Transactions(1679774000, TransactionsRequest{Limit: 1000})
+	if !errors.Is(err, ErrInvalidParameter) {
+		t.Errorf("Expected %s, got %v", ErrInvalidParameter, err)
+	}
+	if calls != 0 {
+		t.Errorf("Expected no calls, got %d", calls)
+	}
+	_, err = api.With(WithValidation(false)).Transactions(1679774000, TransactionsRequest{Limit: 1000})
+	if err != nil {
+		t.Errorf("Expected nil, got %s", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected %d call, got %d", 1, calls)
+	}
+}
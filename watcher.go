@@ -0,0 +1,231 @@
+package whalealertapi
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+// DefaultWatchInterval is time between two polls of /transactions done by Watcher
+const DefaultWatchInterval = time.Minute
+
+// watchOverlap is how far before poll time start is moved after a poll without transactions,
+// so transactions which appear with a delay are still returned
+const watchOverlap = 5 * time.Minute
+
+// Watcher polls /transactions and delivers every new transaction once
+// It advances start timestamp and cursor after every poll, and deduplicates transactions by ID
+// across overlapping windows
+type Watcher struct {
+	api      WhaleAlertAPI
+	filter   TransactionsRequest
+	interval time.Duration
+	start    uint
+	cursor   string
+	seen     map[string]uint
+	store    CheckpointStore
+	now      func() time.Time
+}
+
+// NewWatcher returns Watcher which polls transactions matching filter
+// Start and Cursor of filter are used as initial position, when Start is not set watching starts from now
+func (api WhaleAlertAPI) NewWatcher(filter TransactionsRequest) *Watcher {
+	return &Watcher{
+		api:      api,
+		filter:   filter,
+		interval: DefaultWatchInterval,
+		start:    filter.Start,
+		cursor:   filter.Cursor,
+		seen:     map[string]uint{},
+		now:      time.Now,
+	}
+}
+
+// Watch starts Watcher with default interval, see Watcher.Run
+func (api WhaleAlertAPI) Watch(ctx context.Context, filter TransactionsRequest) (<-chan Transaction, <-chan error) {
+	return api.NewWatcher(filter).Run(ctx)
+}
+
+// WithInterval sets time between two polls, DefaultWatchInterval is used when interval is not positive
+func (w *Watcher) WithInterval(interval time.Duration) *Watcher {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	w.interval = interval
+	return w
+}
+
+// WithStart sets timestamp from which transactions are watched
+func (w *Watcher) WithStart(start uint) *Watcher {
+	w.start = start
+	return w
+}
+
+// WithCheckpointStore sets store used to resume watching, checkpoint is loaded when Run starts
+// and saved after every poll, so transactions are delivered at least once across restarts
+func (w *Watcher) WithCheckpointStore(store CheckpointStore) *Watcher {
+	w.store = store
+	return w
+}
+
+// Run polls /transactions until ctx is done and returns channels with new transactions and errors
+// Transient errors are sent to error channel and polling continues, errors which won't go away
+// (unauthorized, invalid parameter, missing url or key) are sent and then polling stops.
+// Both channels are closed when Run stops, both must be drained by the caller.
+// Watcher must not be run more than once at a time.
+func (w *Watcher) Run(ctx context.Context) (<-chan Transaction, <-chan error) {
+	transactions := make(chan Transaction)
+	errs := make(chan error)
+	go func() {
+		defer close(transactions)
+		defer close(errs)
+		if err := w.restore(ctx); err != nil {
+			select {
+			case errs <- err:
+			case <-ctx.Done():
+			}
+			return
+		}
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+		for {
+			err := w.poll(ctx, transactions)
+			if ctx.Err() != nil {
+				return
+			}
+			if saveErr := w.commit(ctx); saveErr != nil && err == nil {
+				err = saveErr
+			}
+			if err != nil {
+				select {
+				case errs <- err:
+				case <-ctx.Done():
+					return
+				}
+				if isPermanentError(err) {
+					return
+				}
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return transactions, errs
+}
+
+// poll fetches all pages available since last position and sends new transactions to out
+func (w *Watcher) poll(ctx context.Context, out chan<- Transaction) error {
+	filter := w.filter
+	filter.Cursor = w.cursor
+	polled := w.now()
+	it := w.api.IterateTransactions(ctx, w.start, filter)
+	latest := w.start
+	empty := true
+	for it.Next() {
+		tx := it.Transaction()
+		empty = false
+		if tx.Timestamp > latest {
+			latest = tx.Timestamp
+		}
+		if _, ok := w.seen[tx.ID]; ok {
+			continue
+		}
+		w.seen[tx.ID] = tx.Timestamp
+		select {
+		case out <- tx:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if it.Cursor() != "" {
+		w.cursor = it.Cursor()
+	}
+	if latest > 0 {
+		// Start is exclusive and more transactions can appear with the latest timestamp
+		w.advance(latest - 1)
+	}
+	if err := it.Err(); err != nil {
+		return err
+	}
+	if empty {
+		w.catchUp(polled)
+	}
+	return nil
+}
+
+// catchUp moves start of a quiet filter close to poll time, otherwise start falls behind lookback
+// of the plan and requests are rejected, overlap is at most half of the lookback
+func (w *Watcher) catchUp(polled time.Time) {
+	overlap := watchOverlap
+	if lookback := w.api.plan.MaxLookback; lookback > 0 && overlap > lookback/2 {
+		overlap = lookback / 2
+	}
+	if start := polled.Add(-overlap).Unix(); start > 0 {
+		w.advance(uint(start))
+	}
+}
+
+// advance moves start forward
+// Transactions older than start are forgotten, as they won't be returned again
+func (w *Watcher) advance(start uint) {
+	if start <= w.start {
+		return
+	}
+	w.start = start
+	for id, timestamp := range w.seen {
+		if timestamp <= w.start {
+			delete(w.seen, id)
+		}
+	}
+}
+
+// restore sets position from checkpoint store, or from now when there is no position yet
+func (w *Watcher) restore(ctx context.Context) error {
+	if w.store != nil {
+		checkpoint, err := w.store.Load(ctx)
+		if err != nil {
+			return err
+		}
+		if checkpoint != nil {
+			w.start = checkpoint.Timestamp
+			w.cursor = checkpoint.Cursor
+			for _, id := range checkpoint.SeenIDs {
+				w.seen[id] = checkpoint.Timestamp + 1
+			}
+		}
+	}
+	if w.start == 0 {
+		w.start = uint(w.now().Unix())
+	}
+	return nil
+}
+
+// commit saves current position to checkpoint store
+func (w *Watcher) commit(ctx context.Context) error {
+	if w.store == nil {
+		return nil
+	}
+	seen := make([]string, 0, len(w.seen))
+	for id := range w.seen {
+		seen = append(seen, id)
+	}
+	sort.Strings(seen)
+	return w.store.Save(ctx, Checkpoint{
+		Timestamp: w.start,
+		Cursor:    w.cursor,
+		SeenIDs:   seen,
+		UpdatedAt: w.now(),
+	})
+}
+
+// isPermanentError returns true for errors which won't disappear when request is repeated
+func isPermanentError(err error) bool {
+	return errors.Is(err, ErrUnauthorized) ||
+		errors.Is(err, ErrInvalidParameter) ||
+		errors.Is(err, ErrMissingURL) ||
+		errors.Is(err, ErrMissingAccessKey)
+}
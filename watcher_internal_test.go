@@ -0,0 +1,86 @@
+package whalealertapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWatcherQuietFilter(t *testing.T) {
+	var mu sync.Mutex
+	now := time.Unix(1679774500, 0)
+	polls, rejected := 0, 0
+	lastStart := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		polls++
+		lastStart, _ = strconv.Atoi(r.URL.Query().Get("start"))
+		// Server rejects requests older than lookback of the free plan, like the real API
+		if now.Sub(time.Unix(int64(lastStart), 0)) > PlanFree.MaxLookback {
+			rejected++
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"result":"error","message":"start is too old"}`))
+			return
+		}
+		w.Write([]byte(`{"result":"success","cursor":"0-0-0","count":0}`))
+	}))
+	defer server.Close()
+
+	api := New(WithURL(server.URL), WithAccessKey("OK"), WithPlan(PlanFree), WithValidation(false))
+	watcher := api.NewWatcher(TransactionsRequest{MinValue: 500000}).WithInterval(time.Millisecond)
+	watcher.now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	transactions, errs := watcher.Run(ctx)
+
+	// Clock moves by twice the lookback in steps, every step is polled
+	for step := 0; step < 12; step++ {
+		mu.Lock()
+		now = now.Add(10 * time.Minute)
+		wanted := polls + 2
+		mu.Unlock()
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			mu.Lock()
+			count := polls
+			mu.Unlock()
+			if count >= wanted {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("Expected watcher to keep polling, it stopped after %d polls", count)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	cancel()
+	for range transactions {
+	}
+	for range errs {
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if rejected != 0 {
+		t.Errorf("Expected no rejected polls, got %d", rejected)
+	}
+	if age := now.Sub(time.Unix(int64(lastStart), 0)); age > 10*time.Minute+watchOverlap {
+		t.Errorf("Expected start to follow the clock, got start %s old", age)
+	}
+}
+
+func TestWatcherInterval(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Second} {
+		if w := New().NewWatcher(TransactionsRequest{}).WithInterval(interval); w.interval != DefaultWatchInterval {
+			t.Errorf("Expected %s for %s, got %s", DefaultWatchInterval, interval, w.interval)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package whalealertapi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	whalealertapi "github.com/devbay-io/whale_alert_api_client"
+)
+
+func TestWatcher(t *testing.T) {
+	var mu sync.Mutex
+	ids := []int{1, 2}
+	failWith := 0
+	starts := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		starts = append(starts, r.URL.Query().Get("start"))
+		if failWith != 0 {
+			w.WriteHeader(failWith)
+			w.Write([]byte(`{"result":"error","message":"failure"}`))
+			failWith = 0
+			return
+		}
+		start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+		page := []int{}
+		for _, id := range ids {
+			if 1679774500+id > start {
+				page = append(page, id)
+			}
+		}
+		w.Write([]byte(transactionsPage("c"+strconv.Itoa(len(ids)), page...)))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	api := whalealertapi.New().WithCustomURL(server.URL).WithAccessKey("CORRECT")
+	transactions, errs := api.NewWatcher(whalealertapi.TransactionsRequest{Start: 1679774500}).
+		WithInterval(10 * time.Millisecond).
+		Run(ctx)
+
+	expectTransaction := func(id string) {
+		t.Helper()
+		select {
+		case tx := <-transactions:
+			if tx.ID != id {
+				t.Errorf("Expected transaction %s, got %s", id, tx.ID)
+			}
+		case err := <-errs:
+			t.Fatalf("Expected transaction %s, got error %s", id, err)
+		case <-ctx.Done():
+			t.Fatalf("Expected transaction %s, got timeout", id)
+		}
+	}
+	expectError := func(sentinel error) {
+		t.Helper()
+		select {
+		case tx := <-transactions:
+			t.Fatalf("Expected error %s, got transaction %s", sentinel, tx.ID)
+		case err := <-errs:
+			if !errors.Is(err, sentinel) {
+				t.Errorf("Expected %s, got %s", sentinel, err)
+			}
+		case <-ctx.Done():
+			t.Fatalf("Expected error %s, got timeout", sentinel)
+		}
+	}
+
+	expectTransaction("1")
+	expectTransaction("2")
+
+	mu.Lock()
+	ids = append(ids, 3)
+	mu.Unlock()
+	expectTransaction("3")
+
+	mu.Lock()
+	failWith = http.StatusServiceUnavailable
+	mu.Unlock()
+	expectError(whalealertapi.ErrServerError)
+
+	mu.Lock()
+	ids = append(ids, 4)
+	mu.Unlock()
+	expectTransaction("4")
+
+	mu.Lock()
+	failWith = http.StatusUnauthorized
+	mu.Unlock()
+	expectError(whalealertapi.ErrUnauthorized)
+
+	if _, ok := <-transactions; ok {
+		t.Errorf("Expected transactions channel to be closed")
+	}
+	if _, ok := <-errs; ok {
+		t.Errorf("Expected errors channel to be closed")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if starts[0] != "1679774500" {
+		t.Errorf("Expected first poll to start at %s, got %s", "1679774500", starts[0])
+	}
+	// Start is exclusive, so the second of the latest transaction is polled again
+	if last := starts[len(starts)-1]; last != "1679774503" {
+		t.Errorf("Expected last poll to start at %s, got %s", "1679774503", last)
+	}
+}
+
+func TestWatchStopsOnContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"result":"success","cursor":"0-0-0","count":0}`))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	api := whalealertapi.New().WithCustomURL(server.URL).WithAccessKey("CORRECT")
+	transactions, errs := api.Watch(ctx, whalealertapi.TransactionsRequest{})
+	cancel()
+
+	timeout := time.After(5 * time.Second)
+	for transactions != nil || errs != nil {
+		select {
+		case _, ok := <-transactions:
+			if !ok {
+				transactions = nil
+			}
+		case _, ok := <-errs:
+			if !ok {
+				errs = nil
+			}
+		case <-timeout:
+			t.Fatalf("Expected channels to be closed after context is cancelled")
+		}
+	}
+}
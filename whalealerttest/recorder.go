@@ -0,0 +1,240 @@
+package whalealerttest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ErrNoInteraction is returned by Recorder in strict replay mode when request matches no recorded interaction
+var ErrNoInteraction = errors.New("no recorded interaction matches request")
+
+// Mode is mode of Recorder
+type Mode int
+
+const (
+	// ModeReplay serves responses from cassette
+	ModeReplay Mode = iota
+	// ModeRecord sends requests to the API and records them to cassette
+	ModeRecord
+)
+
+// Cassette is list of recorded interactions, it is saved as JSON
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is recorded request and its response
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest identifies request, query is normalized and doesn't contain access key
+type RecordedRequest struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Query  string `json:"query,omitempty"`
+}
+
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body"`
+}
+
+// Recorder is http.RoundTripper which records API traffic to cassette file or replays it
+// Access key is never saved: X-WA-API-KEY header is not recorded, api_key query parameter is dropped
+// and the key is replaced with REDACTED in recorded responses
+//
+//	rec, err := whalealerttest.NewRecorder("testdata/status.json", whalealerttest.ModeReplay)
+//	api := whalealertapi.New(whalealertapi.WithAccessKey("key"), whalealertapi.WithTransport(rec))
+//
+// In replay mode requests are matched by method, path and normalized query,
+// identical requests get recorded responses in order and the last one is repeated when they run out.
+// Recorder is strict by default, unmatched request fails with ErrNoInteraction,
+// with WithStrict(false) it is sent with transport instead.
+type Recorder struct {
+	path      string
+	mode      Mode
+	strict    bool
+	transport http.RoundTripper
+
+	mu       sync.Mutex
+	cassette Cassette
+	// played is number of replayed responses of every request
+	played map[RecordedRequest]int
+}
+
+// NewRecorder returns recorder using cassette at path, in replay mode the cassette is loaded
+func NewRecorder(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{
+		path:      path,
+		mode:      mode,
+		strict:    true,
+		transport: http.DefaultTransport,
+		played:    map[RecordedRequest]int{},
+	}
+	if mode == ModeReplay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &r.cassette); err != nil {
+			return nil, fmt.Errorf("invalid cassette %s: %w", path, err)
+		}
+	}
+	return r, nil
+}
+
+// WithTransport sets transport used to send recorded and unmatched requests, http.DefaultTransport is used by default
+func (r *Recorder) WithTransport(transport http.RoundTripper) *Recorder {
+	r.transport = transport
+	return r
+}
+
+// WithStrict sets whether unmatched requests fail in replay mode
+func (r *Recorder) WithStrict(strict bool) *Recorder {
+	r.strict = strict
+	return r
+}
+
+// Interactions returns recorded or loaded interactions
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Interaction{}, r.cassette.Interactions...)
+}
+
+// RoundTrip records or replays request
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if r.mode == ModeRecord {
+		return r.record(req)
+	}
+	if res, ok := r.replay(req); ok {
+		return res, nil
+	}
+	if r.strict {
+		key := recordedRequest(req)
+		return nil, fmt.Errorf("%w: %s %s?%s", ErrNoInteraction, key.Method, key.Path, key.Query)
+	}
+	return r.transport.RoundTrip(req)
+}
+
+// Save writes recorded interactions to cassette file, it does nothing in replay mode
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, append(data, '\n'), 0o644)
+}
+
+func (r *Recorder) record(req *http.Request) (*http.Response, error) {
+	res, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+
+	key := req.Header.Get("X-WA-API-KEY")
+	if key == "" {
+		key = req.URL.Query().Get("api_key")
+	}
+	header := http.Header{}
+	for name, values := range res.Header {
+		if name == "Set-Cookie" || name == "Content-Length" {
+			continue
+		}
+		for _, value := range values {
+			header.Add(name, scrub(value, key))
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: recordedRequest(req),
+		Response: RecordedResponse{
+			StatusCode: res.StatusCode,
+			Header:     header,
+			Body:       scrub(string(body), key),
+		},
+	})
+	return res, nil
+}
+
+func (r *Recorder) replay(req *http.Request) (*http.Response, bool) {
+	key := recordedRequest(req)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	matches := []RecordedResponse{}
+	for _, interaction := range r.cassette.Interactions {
+		if interaction.Request == key {
+			matches = append(matches, interaction.Response)
+		}
+	}
+	if len(matches) == 0 {
+		return nil, false
+	}
+	i := r.played[key]
+	if i >= len(matches) {
+		i = len(matches) - 1
+	}
+	r.played[key]++
+	recorded := matches[i]
+	header := recorded.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}, true
+}
+
+// recordedRequest returns method, path and normalized query of req, access key is dropped from query
+func recordedRequest(req *http.Request) RecordedRequest {
+	query := req.URL.Query()
+	query.Del("api_key")
+	return RecordedRequest{
+		Method: req.Method,
+		Path:   "/" + strings.Trim(req.URL.Path, "/"),
+		// Encode sorts parameters by key, so their order doesn't matter
+		Query: query.Encode(),
+	}
+}
+
+// scrub replaces access key in s
+func scrub(s, key string) string {
+	if key == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, key, "REDACTED")
+}
@@ -0,0 +1,109 @@
+package whalealerttest_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	whalealertapi "github.com/devbay-io/whale_alert_api_client"
+	"github.com/devbay-io/whale_alert_api_client/whalealerttest"
+)
+
+func TestRecorder(t *testing.T) {
+	srv := whalealerttest.NewServer().WithKey("SECRETKEY").AddTransactions(transactions...)
+	defer srv.Close()
+	cassette := filepath.Join(t.TempDir(), "testdata", "cassette.json")
+
+	rec, err := whalealerttest.NewRecorder(cassette, whalealerttest.ModeRecord)
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err)
+	}
+	api := srv.Client(whalealertapi.WithTransport(rec))
+	status, err := api.Status()
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err)
+	}
+	transaction, err := api.Transaction("bitcoin", "bb")
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err)
+	}
+	args := whalealertapi.TransactionsRequest{MinValue: 500000, Limit: 10}
+	page, err := api.Transactions(1679774500, args)
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err)
+	}
+	if _, err := api.Transaction("dogecoin", "aa"); err == nil {
+		t.Fatalf("Expected error for unknown blockchain")
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatalf("Expected nil, got %s", err)
+	}
+	data, err := os.ReadFile(cassette)
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err)
+	}
+	if strings.Contains(string(data), "SECRETKEY") {
+		t.Errorf("Expected access key to be scrubbed, got %s", data)
+	}
+	if len(rec.Interactions()) != 4 {
+		t.Errorf("Expected %d interactions, got %d", 4, len(rec.Interactions()))
+	}
+
+	// Replay doesn't need the server, query order and access key don't matter
+	srv.Close()
+	replay, err := whalealerttest.NewRecorder(cassette, whalealerttest.ModeReplay)
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err)
+	}
+	api = whalealertapi.New(whalealertapi.WithURL(srv.URL()), whalealertapi.WithAccessKey("OTHER"), whalealertapi.WithTransport(replay))
+	replayedStatus, err := api.Status()
+	if err != nil || !reflect.DeepEqual(replayedStatus, status) {
+		t.Errorf("Expected %v, got %v %v", status, replayedStatus, err)
+	}
+	replayedTransaction, err := api.TransactionContext(context.Background(), "bitcoin", "bb")
+	if err != nil || !reflect.DeepEqual(replayedTransaction, transaction) {
+		t.Errorf("Expected %v, got %v %v", transaction, replayedTransaction, err)
+	}
+	replayedPage, err := api.Transactions(1679774500, args)
+	if err != nil || !reflect.DeepEqual(replayedPage, page) {
+		t.Errorf("Expected %v, got %v %v", page, replayedPage, err)
+	}
+	if _, err := api.Transaction("dogecoin", "aa"); !errors.Is(err, whalealertapi.ErrInvalidParameter) {
+		t.Errorf("Expected %s, got %v", whalealertapi.ErrInvalidParameter, err)
+	}
+
+	// Strict replay fails unmatched requests
+	if _, err := api.Transaction("bitcoin", "other"); !errors.Is(err, whalealerttest.ErrNoInteraction) {
+		t.Errorf("Expected %s, got %v", whalealerttest.ErrNoInteraction, err)
+	}
+}
+
+func TestRecorderPassThrough(t *testing.T) {
+	srv := whalealerttest.NewServer().AddTransactions(transactions...)
+	defer srv.Close()
+	cassette := filepath.Join(t.TempDir(), "cassette.json")
+	if err := os.WriteFile(cassette, []byte(`{"interactions":[]}`), 0o644); err != nil {
+		t.Fatalf("Expected nil, got %s", err)
+	}
+
+	rec, err := whalealerttest.NewRecorder(cassette, whalealerttest.ModeReplay)
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err)
+	}
+	rec.WithStrict(false)
+	res, err := srv.Client(whalealertapi.WithTransport(rec)).Status()
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err)
+	}
+	if res.BlockchainCount != 3 {
+		t.Errorf("Expected %d got: %d", 3, res.BlockchainCount)
+	}
+
+	if _, err := whalealerttest.NewRecorder(filepath.Join(t.TempDir(), "missing.json"), whalealerttest.ModeReplay); err == nil {
+		t.Errorf("Expected error for missing cassette")
+	}
+}
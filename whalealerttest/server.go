@@ -0,0 +1,393 @@
+// Package whalealerttest provides in-memory fake of Whale Alert API for tests
+//
+//	srv := whalealerttest.NewServer().AddTransactions(txs...)
+//	defer srv.Close()
+//	api := srv.Client()
+package whalealerttest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	whalealertapi "github.com/devbay-io/whale_alert_api_client"
+)
+
+// DefaultKey is API key accepted by Server unless changed with WithKey
+const DefaultKey = "whalealerttest"
+
+// MaxLimit is the biggest limit accepted by /transactions
+const MaxLimit = 100
+
+// emptyCursor is cursor returned by the API when there are no transactions
+const emptyCursor = "0-0-0"
+
+// fault is response which replaces next response of matching endpoint
+type fault struct {
+	endpoint string
+	status   int
+	message  string
+	header   http.Header
+}
+
+// Server is fake Whale Alert API serving seeded transactions and blockchains
+// It is safe for concurrent use
+type Server struct {
+	server       *httptest.Server
+	mu           sync.Mutex
+	key          string
+	transactions []whalealertapi.Transaction
+	blockchains  []whalealertapi.Blockchain
+	latency      time.Duration
+	faults       []fault
+	requests     int
+}
+
+// NewServer starts new Server with no data, it must be closed with Close
+func NewServer() *Server {
+	s := &Server{key: DefaultKey}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Close shuts down the server
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// URL returns base url of the server
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+// Key returns API key accepted by the server
+func (s *Server) Key() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.key
+}
+
+// Client returns API client configured to use this server, options are applied after url and key
+func (s *Server) Client(opts ...whalealertapi.Option) *whalealertapi.WhaleAlertAPI {
+	opts = append([]whalealertapi.Option{whalealertapi.WithURL(s.URL()), whalealertapi.WithAccessKey(s.Key())}, opts...)
+	return whalealertapi.New(opts...)
+}
+
+// WithKey sets API key accepted by the server
+func (s *Server) WithKey(key string) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.key = key
+	return s
+}
+
+// WithLatency delays every response by given duration
+func (s *Server) WithLatency(latency time.Duration) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = latency
+	return s
+}
+
+// AddTransactions adds transactions to the dataset
+func (s *Server) AddTransactions(transactions ...whalealertapi.Transaction) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.transactions = append(s.transactions, transactions...)
+	sort.SliceStable(s.transactions, func(i, j int) bool {
+		return transactionLess(s.transactions[i], s.transactions[j])
+	})
+	return s
+}
+
+// AddBlockchains adds blockchains returned by /status
+// When no blockchains are added, they are derived from transactions
+func (s *Server) AddBlockchains(blockchains ...whalealertapi.Blockchain) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.blockchains = append(s.blockchains, blockchains...)
+	return s
+}
+
+// FailNext makes next request to endpoint fail with given status and message
+// Endpoint is matched by whole path segments, so "/transaction" matches "/transaction/bitcoin/abc"
+// but not "/transactions", empty endpoint matches all
+func (s *Server) FailNext(endpoint string, status int, message string) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, fault{endpoint: endpoint, status: status, message: message})
+	return s
+}
+
+// RateLimitNext makes next n requests fail with 429 and given Retry-After
+func (s *Server) RateLimitNext(n int, retryAfter time.Duration) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	header := http.Header{}
+	header.Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())))
+	for i := 0; i < n; i++ {
+		s.faults = append(s.faults, fault{status: http.StatusTooManyRequests, message: "usage limit reached", header: header})
+	}
+	return s
+}
+
+// Requests returns number of requests received by the server
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests++
+	latency := s.latency
+	key := s.key
+	f, faulted := s.takeFault(r.URL.Path)
+	s.mu.Unlock()
+
+	if latency > 0 {
+		timer := time.NewTimer(latency)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-r.Context().Done():
+			return
+		}
+	}
+	if faulted {
+		for k, v := range f.header {
+			w.Header()[k] = v
+		}
+		writeError(w, f.status, f.message)
+		return
+	}
+	if r.Header.Get("X-WA-API-KEY") != key && r.URL.Query().Get("api_key") != key {
+		writeError(w, http.StatusUnauthorized, "invalid api_key")
+		return
+	}
+
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case len(segments) == 1 && segments[0] == "status":
+		s.serveStatus(w)
+	case len(segments) == 3 && segments[0] == "transaction":
+		s.serveTransaction(w, whalealertapi.BlockchainName(segments[1]), segments[2])
+	case len(segments) == 1 && segments[0] == "transactions":
+		s.serveTransactions(w, r)
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+// takeFault removes and returns first fault matching path, must be called with lock held
+func (s *Server) takeFault(path string) (fault, bool) {
+	for i, f := range s.faults {
+		if f.matches(path) {
+			s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			return f, true
+		}
+	}
+	return fault{}, false
+}
+
+// matches reports if path is the endpoint of the fault or is below it
+func (f fault) matches(path string) bool {
+	endpoint := strings.TrimSuffix(f.endpoint, "/")
+	return endpoint == "" || path == endpoint || strings.HasPrefix(path, endpoint+"/")
+}
+
+func (s *Server) serveStatus(w http.ResponseWriter) {
+	blockchains := s.statusBlockchains()
+	writeJSON(w, whalealertapi.StatusResponse{
+		Result:          "success",
+		BlockchainCount: uint(len(blockchains)),
+		Blockchains:     blockchains,
+	})
+}
+
+// statusBlockchains returns seeded blockchains or derives them from transactions
+func (s *Server) statusBlockchains() []whalealertapi.Blockchain {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.blockchains) > 0 {
+		return append([]whalealertapi.Blockchain{}, s.blockchains...)
+	}
+	blockchains := []whalealertapi.Blockchain{}
+	index := map[whalealertapi.BlockchainName]int{}
+	for _, tx := range s.transactions {
+		i, ok := index[tx.Blockchain]
+		if !ok {
+			i = len(blockchains)
+			index[tx.Blockchain] = i
+			blockchains = append(blockchains, whalealertapi.Blockchain{Name: tx.Blockchain, Status: whalealertapi.BlockchainConnected})
+		}
+		if !contains(blockchains[i].Symbols, tx.Symbol) {
+			blockchains[i].Symbols = append(blockchains[i].Symbols, tx.Symbol)
+		}
+	}
+	return blockchains
+}
+
+func (s *Server) serveTransaction(w http.ResponseWriter, blockchain whalealertapi.BlockchainName, hash string) {
+	known := false
+	for _, b := range s.statusBlockchains() {
+		if b.Name == blockchain {
+			known = true
+		}
+	}
+	if !known {
+		writeError(w, http.StatusBadRequest, "invalid value for blockchain parameter")
+		return
+	}
+	s.mu.Lock()
+	transactions := []whalealertapi.Transaction{}
+	for _, tx := range s.transactions {
+		if tx.Blockchain == blockchain && tx.Hash == hash {
+			transactions = append(transactions, tx)
+		}
+	}
+	s.mu.Unlock()
+	writeJSON(w, whalealertapi.TransactionResponse{
+		Result:       "success",
+		Count:        uint(len(transactions)),
+		Transactions: transactions,
+	})
+}
+
+func (s *Server) serveTransactions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	start, err := parseUint(query.Get("start"))
+	if err != nil || start == 0 {
+		writeError(w, http.StatusBadRequest, "invalid value for start parameter")
+		return
+	}
+	end, err := parseUint(query.Get("end"))
+	if err != nil || (end != 0 && end <= start) {
+		writeError(w, http.StatusBadRequest, "invalid value for end parameter")
+		return
+	}
+	minValue, err := parseUint(query.Get("min_value"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid value for min_value parameter")
+		return
+	}
+	limit, err := parseUint(query.Get("limit"))
+	if err != nil || limit > MaxLimit {
+		writeError(w, http.StatusBadRequest, "invalid value for limit parameter")
+		return
+	}
+	if limit == 0 {
+		limit = MaxLimit
+	}
+	cursor := query.Get("cursor")
+	after, hasCursor, err := parseCursor(cursor)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid value for cursor parameter")
+		return
+	}
+	currency := query.Get("currency")
+
+	s.mu.Lock()
+	transactions := []whalealertapi.Transaction{}
+	for _, tx := range s.transactions {
+		if uint(len(transactions)) == limit {
+			break
+		}
+		if hasCursor && !transactionLess(after, tx) {
+			continue
+		}
+		if tx.Timestamp <= start || (end != 0 && tx.Timestamp > end) {
+			continue
+		}
+		if tx.AmountUSD.Cmp(whalealertapi.NewDecimalFromInt(int64(minValue))) < 0 {
+			continue
+		}
+		if currency != "" && tx.Symbol != currency {
+			continue
+		}
+		transactions = append(transactions, tx)
+	}
+	s.mu.Unlock()
+
+	if len(transactions) > 0 {
+		cursor = formatCursor(transactions[len(transactions)-1])
+	}
+	if cursor == "" {
+		cursor = emptyCursor
+	}
+	writeJSON(w, whalealertapi.TransactionsResponse{
+		Result:       "success",
+		Cursor:       cursor,
+		Count:        uint(len(transactions)),
+		Transactions: transactions,
+	})
+}
+
+// transactionLess orders transactions by timestamp and then by ID
+func transactionLess(a, b whalealertapi.Transaction) bool {
+	if a.Timestamp != b.Timestamp {
+		return a.Timestamp < b.Timestamp
+	}
+	if len(a.ID) != len(b.ID) {
+		return len(a.ID) < len(b.ID)
+	}
+	return a.ID < b.ID
+}
+
+// formatCursor returns cursor pointing after given transaction
+func formatCursor(tx whalealertapi.Transaction) string {
+	return fmt.Sprintf("%x-%s", tx.Timestamp, tx.ID)
+}
+
+// parseCursor returns position encoded in cursor, empty cursor and emptyCursor point before all transactions
+func parseCursor(cursor string) (whalealertapi.Transaction, bool, error) {
+	if cursor == "" || cursor == emptyCursor {
+		return whalealertapi.Transaction{}, false, nil
+	}
+	timestamp, id, ok := strings.Cut(cursor, "-")
+	if !ok || id == "" {
+		return whalealertapi.Transaction{}, false, fmt.Errorf("invalid cursor %q", cursor)
+	}
+	ts, err := strconv.ParseUint(timestamp, 16, 64)
+	if err != nil {
+		return whalealertapi.Transaction{}, false, err
+	}
+	return whalealertapi.Transaction{Timestamp: uint(ts), ID: id}, true, nil
+}
+
+// parseUint parses query parameter, empty value is 0
+func parseUint(value string) (uint, error) {
+	if value == "" {
+		return 0, nil
+	}
+	v, err := strconv.ParseUint(value, 10, 64)
+	return uint(v), err
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(whalealertapi.ErrorResponse{Result: "error", Message: message})
+}
@@ -0,0 +1,184 @@
+package whalealerttest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	whalealertapi "github.com/devbay-io/whale_alert_api_client"
+	"github.com/devbay-io/whale_alert_api_client/whalealerttest"
+)
+
+var transactions = []whalealertapi.Transaction{
+	{Blockchain: "ethereum", Symbol: "usdt", ID: "10", Hash: "aa", Timestamp: 1679774510, AmountUSD: whalealertapi.NewDecimalFromInt(600000)},
+	{Blockchain: "ethereum", Symbol: "usdc", ID: "11", Hash: "aa", Timestamp: 1679774510, AmountUSD: whalealertapi.NewDecimalFromInt(700000)},
+	{Blockchain: "bitcoin", Symbol: "btc", ID: "9", Hash: "bb", Timestamp: 1679774520, AmountUSD: whalealertapi.NewDecimalFromInt(2000000)},
+	{Blockchain: "ethereum", Symbol: "usdt", ID: "12", Hash: "cc", Timestamp: 1679774530, AmountUSD: whalealertapi.NewDecimalFromInt(100000)},
+	{Blockchain: "tron", Symbol: "usdt", ID: "13", Hash: "dd", Timestamp: 1679774540, AmountUSD: whalealertapi.NewDecimalFromInt(5000000)},
+}
+
+func TestStatus(t *testing.T) {
+	srv := whalealerttest.NewServer().AddTransactions(transactions...)
+	defer srv.Close()
+
+	res, err := srv.Client().Status()
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err)
+	}
+	if res.BlockchainCount != 3 {
+		t.Errorf("Expected %d, got %d", 3, res.BlockchainCount)
+	}
+	if res.Blockchains[0].Name != "ethereum" || len(res.Blockchains[0].Symbols) != 2 {
+		t.Errorf("Expected ethereum with 2 symbols, got %v", res.Blockchains[0])
+	}
+
+	srv.AddBlockchains(whalealertapi.Blockchain{Name: "ripple", Symbols: []string{"xrp"}, Status: "connected"})
+	res, err = srv.Client().Status()
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err)
+	}
+	if res.BlockchainCount != 1 || res.Blockchains[0].Name != "ripple" {
+		t.Errorf("Expected only seeded blockchain, got %v", res.Blockchains)
+	}
+}
+
+func TestTransaction(t *testing.T) {
+	srv := whalealerttest.NewServer().AddTransactions(transactions...)
+	defer srv.Close()
+	api := srv.Client()
+
+	res, err := api.Transaction("ethereum", "aa")
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err)
+	}
+	if res.Count != 2 {
+		t.Errorf("Expected %d, got %d", 2, res.Count)
+	}
+	res, err = api.Transaction("bitcoin", "aa")
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err)
+	}
+	if res.Count != 0 {
+		t.Errorf("Expected %d, got %d", 0, res.Count)
+	}
+	_, err = api.Transaction("abc", "aa")
+	if !errors.Is(err, whalealertapi.ErrInvalidParameter) {
+		t.Errorf("Expected %s, got %v", whalealertapi.ErrInvalidParameter, err)
+	}
+}
+
+func TestTransactions(t *testing.T) {
+	srv := whalealerttest.NewServer().AddTransactions(transactions...)
+	defer srv.Close()
+	api := srv.Client()
+
+	res, err := api.Transactions(1679774510, whalealertapi.TransactionsRequest{})
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err)
+	}
+	if res.Count != 3 {
+		t.Errorf("Expected %d transactions after start, got %d", 3, res.Count)
+	}
+
+	res, err = api.Transactions(1679774500, whalealertapi.TransactionsRequest{End: 1679774530, MinValue: 500000, Currency: "usdt"})
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err)
+	}
+	if res.Count != 1 || res.Transactions[0].ID != "10" {
+		t.Errorf("Expected only transaction 10, got %v", res.Transactions)
+	}
+
+	res, err = api.Transactions(1679774600, whalealertapi.TransactionsRequest{})
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err)
+	}
+	if res.Count != 0 || res.Cursor != "0-0-0" {
+		t.Errorf("Expected empty page with %s cursor, got %d transactions and %s", "0-0-0", res.Count, res.Cursor)
+	}
+
+	ids := []string{}
+	for tx, err := range api.AllTransactions(context.Background(), 1679774500, whalealertapi.TransactionsRequest{Limit: 2}) {
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err)
+		}
+		ids = append(ids, tx.ID)
+	}
+	expected := []string{"10", "11", "9", "12", "13"}
+	if len(ids) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, ids)
+	}
+	for i := range expected {
+		if ids[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, ids)
+		}
+	}
+
+	_, err = api.With(whalealertapi.WithValidation(false)).Transactions(1679774500, whalealertapi.TransactionsRequest{Limit: 101})
+	var apiErr *whalealertapi.APIError
+	if !errors.As(err, &apiErr) || !errors.Is(err, whalealertapi.ErrInvalidParameter) {
+		t.Errorf("Expected %s, got %v", whalealertapi.ErrInvalidParameter, err)
+	}
+	_, err = api.Transactions(1679774500, whalealertapi.TransactionsRequest{Cursor: "bogus"})
+	if !errors.Is(err, whalealertapi.ErrInvalidParameter) {
+		t.Errorf("Expected %s, got %v", whalealertapi.ErrInvalidParameter, err)
+	}
+}
+
+func TestAccessKey(t *testing.T) {
+	srv := whalealerttest.NewServer().WithKey("SECRET")
+	defer srv.Close()
+
+	_, err := whalealertapi.New().WithCustomURL(srv.URL()).WithAccessKey("WRONG").Status()
+	if !errors.Is(err, whalealertapi.ErrUnauthorized) {
+		t.Errorf("Expected %s, got %v", whalealertapi.ErrUnauthorized, err)
+	}
+	if _, err := srv.Client().Status(); err != nil {
+		t.Errorf("Expected nil, got %s", err)
+	}
+}
+
+func TestFaults(t *testing.T) {
+	srv := whalealerttest.NewServer().AddTransactions(transactions...)
+	defer srv.Close()
+	api := srv.Client()
+
+	srv.FailNext("/transaction", http.StatusInternalServerError, "internal error")
+	if _, err := api.Status(); err != nil {
+		t.Errorf("Expected fault not to match /status, got %s", err)
+	}
+	if _, err := api.Transactions(1679774510, whalealertapi.TransactionsRequest{}); err != nil {
+		t.Errorf("Expected fault not to match /transactions, got %s", err)
+	}
+	_, err := api.Transaction("ethereum", "aa")
+	if !errors.Is(err, whalealertapi.ErrServerError) || err.Error() != "internal error" {
+		t.Errorf("Expected %s, got %v", whalealertapi.ErrServerError, err)
+	}
+	if _, err := api.Transaction("ethereum", "aa"); err != nil {
+		t.Errorf("Expected fault to be used once, got %s", err)
+	}
+
+	srv.RateLimitNext(2, 0)
+	policy := whalealertapi.DefaultRetryPolicy()
+	policy.BaseBackoff = time.Millisecond
+	before := srv.Requests()
+	if _, err := api.With(whalealertapi.WithRetryPolicy(policy)).Status(); err != nil {
+		t.Errorf("Expected retries to succeed, got %s", err)
+	}
+	if srv.Requests()-before != 3 {
+		t.Errorf("Expected %d requests, got %d", 3, srv.Requests()-before)
+	}
+}
+
+func TestLatency(t *testing.T) {
+	srv := whalealerttest.NewServer().WithLatency(time.Second)
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := srv.Client().StatusContext(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected %s, got %v", context.DeadlineExceeded, err)
+	}
+}